@@ -7,6 +7,7 @@ toolchain go1.23.2
 require (
 	github.com/StackExchange/wmi v1.2.1
 	github.com/fsnotify/fsnotify v1.8.0
+	github.com/godbus/dbus/v5 v5.1.0
 	github.com/prometheus/client_golang v1.20.4
 	github.com/shirou/gopsutil v3.21.11+incompatible
 	golang.org/x/sys v0.32.0
@@ -17,6 +17,7 @@ import (
 type Collector struct {
 	mockEnabled      bool
 	deviceConfigPath string
+	deviceConfig     *deviceconfig.Config
 }
 
 func New() *Collector {
@@ -33,6 +34,14 @@ func (c *Collector) RegisterMetrics(reg prometheus.Registerer) error {
 
 	c.mockEnabled = mockconfig.IsEnabled()
 
+	deviceConfig, err := deviceconfig.Read(c.deviceConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to read device config: %w", err)
+	}
+	c.deviceConfig = deviceConfig
+
+	metrics.ConfigureDiskLabels(deviceConfig.Disk.LegacyLabels)
+
 	if c.mockEnabled {
 		metrics.LogMockEnabled()
 		reg.MustRegister(
@@ -47,6 +56,7 @@ func (c *Collector) RegisterMetrics(reg prometheus.Registerer) error {
 			metrics.TotalMemory,
 			metrics.UsedMemory,
 			metrics.FreeMemory,
+			metrics.AvailableMemory,
 			metrics.NetworkErrors,
 			metrics.SerialNumberMetric,
 			metrics.DiskHealthStatus,
@@ -58,22 +68,124 @@ func (c *Collector) RegisterMetrics(reg prometheus.Registerer) error {
 	reg.MustRegister(metrics.ProccessCount)
 	reg.MustRegister(metrics.ProccessMemoryUsage)
 	reg.MustRegister(metrics.ProccessCPUUsage)
+	reg.MustRegister(metrics.ProcessPidCPUSeconds)
 	reg.MustRegister(metrics.ProcessInstanceCount)
 	reg.MustRegister(metrics.ProcessGroupMemoryWorkingSet)
 	reg.MustRegister(metrics.ProcessGroupMemoryPrivate)
 	reg.MustRegister(metrics.ProcessGroupCPUUsage)
+	reg.MustRegister(metrics.ProcessSeriesDropped)
+	reg.MustRegister(metrics.ProcessGroupSeriesDropped)
+	reg.MustRegister(metrics.NamedProcessGroupProcesses)
+	reg.MustRegister(metrics.NamedProcessGroupCPUSeconds)
+	reg.MustRegister(metrics.NamedProcessGroupResidentMemory)
+	reg.MustRegister(metrics.NamedProcessGroupOpenFDs)
+	reg.MustRegister(metrics.NamedProcessGroupThreads)
+	reg.MustRegister(metrics.NamedProcessGroupReadBytes)
+	reg.MustRegister(metrics.NamedProcessGroupWriteBytes)
+	reg.MustRegister(metrics.NamedProcessGroupContextSwitches)
+	reg.MustRegister(metrics.NamedProcessGroupStarts)
+	reg.MustRegister(metrics.NamedProcessGroupExits)
+	reg.MustRegister(metrics.NamedProcessGroupOldestStart)
+	reg.MustRegister(metrics.ProcessRequiredUp)
+	reg.MustRegister(metrics.ProcessForbiddenPresent)
+	reg.MustRegister(metrics.SystemdUp)
+	reg.MustRegister(metrics.SystemdUnitState)
+	reg.MustRegister(metrics.SystemdUnitActive)
+	reg.MustRegister(metrics.SystemdServiceRestarts)
+	reg.MustRegister(metrics.SystemdFailedUnits)
 	reg.MustRegister(metrics.CpuUsage)
 	reg.MustRegister(metrics.CpuTemperature)
+	reg.MustRegister(metrics.CpuSecondsTotal)
+	reg.MustRegister(metrics.LoadAverage)
+	reg.MustRegister(metrics.ContextSwitches)
+	reg.MustRegister(metrics.Interrupts)
+	reg.MustRegister(metrics.Forks)
+	reg.MustRegister(metrics.ProcsRunning)
+	reg.MustRegister(metrics.ProcsBlocked)
+	reg.MustRegister(metrics.CpuFrequency)
+	reg.MustRegister(metrics.CpuThermalThrottle)
+	reg.MustRegister(metrics.CpuInfo)
+	reg.MustRegister(metrics.CpuSocketInfo)
+	reg.MustRegister(metrics.HwmonTemperature)
+	reg.MustRegister(metrics.HwmonTemperatureMax)
+	reg.MustRegister(metrics.HwmonTemperatureCritical)
+	reg.MustRegister(metrics.HwmonFanSpeed)
+	reg.MustRegister(metrics.HwmonFanMin)
+	reg.MustRegister(metrics.HwmonFanMax)
+	reg.MustRegister(metrics.HwmonVoltage)
+	reg.MustRegister(metrics.HwmonVoltageMin)
+	reg.MustRegister(metrics.HwmonVoltageMax)
+	reg.MustRegister(metrics.HwmonCurrent)
+	reg.MustRegister(metrics.HwmonCurrentMax)
+	reg.MustRegister(metrics.HwmonCurrentCritical)
+	reg.MustRegister(metrics.HwmonPower)
+	reg.MustRegister(metrics.HwmonPowerMax)
+	reg.MustRegister(metrics.HwmonPowerCritical)
+	reg.MustRegister(metrics.HwmonAlarm)
+	reg.MustRegister(metrics.PressureStallSeconds)
+	reg.MustRegister(metrics.PressureStallAverage)
+	reg.MustRegister(metrics.PressureSupported)
 	reg.MustRegister(metrics.MemoryModuleInfo)
+	reg.MustRegister(metrics.EdacErrors)
+	reg.MustRegister(metrics.EdacDimmErrors)
+	reg.MustRegister(metrics.EdacCsrowErrors)
 	reg.MustRegister(metrics.TotalMemory)
 	reg.MustRegister(metrics.UsedMemory)
 	reg.MustRegister(metrics.FreeMemory)
+	reg.MustRegister(metrics.AvailableMemory)
+	reg.MustRegister(metrics.MemoryBreakdown)
+	reg.MustRegister(metrics.SwapTotal)
+	reg.MustRegister(metrics.SwapFree)
+	reg.MustRegister(metrics.SwapPages)
+	reg.MustRegister(metrics.PageFaults)
+	reg.MustRegister(metrics.OOMKills)
 	reg.MustRegister(metrics.DiskUsage)
 	reg.MustRegister(metrics.DiskUsagePercent)
 	reg.MustRegister(metrics.DiskReadBytes)
 	reg.MustRegister(metrics.DiskWriteBytes)
 	reg.MustRegister(metrics.DiskHealthStatus)
+	reg.MustRegister(metrics.DiskHealthCheckAge)
+	reg.MustRegister(metrics.DiskTemperature)
+	reg.MustRegister(metrics.DiskTemperatureWarning)
+	reg.MustRegister(metrics.DiskTemperatureCritical)
+	reg.MustRegister(metrics.RemovableDeviceInfo)
+	reg.MustRegister(metrics.RemovableDeviceEvents)
+	reg.MustRegister(metrics.NvmeControllerInfo)
+	reg.MustRegister(metrics.NvmeNamespaceSize)
+	reg.MustRegister(metrics.NvmePCIeLinkSpeed)
+	reg.MustRegister(metrics.NvmePCIeLinkWidth)
+	reg.MustRegister(metrics.NvmeCriticalWarning)
+	reg.MustRegister(metrics.NvmePercentageUsed)
+	reg.MustRegister(metrics.NvmeDataWrittenBytes)
+	reg.MustRegister(metrics.NvmeThermalThrottleTransitions)
+	reg.MustRegister(metrics.NvmeThermalThrottleSeconds)
+	reg.MustRegister(metrics.NetworkInterfaceInfo)
 	reg.MustRegister(metrics.NetworkStatus)
+	reg.MustRegister(metrics.NetworkOperState)
+	reg.MustRegister(metrics.NetworkCarrier)
+	reg.MustRegister(metrics.NetworkCarrierChanges)
+	reg.MustRegister(metrics.NetworkSpeed)
+	reg.MustRegister(metrics.NetworkDuplex)
+	reg.MustRegister(metrics.NetworkMTU)
+	reg.MustRegister(metrics.NetworkAddressInfo)
+	reg.MustRegister(metrics.NetworkDefaultGateway)
+	reg.MustRegister(metrics.NetworkRoutes)
+	reg.MustRegister(metrics.NetworkDNSResolverInfo)
+	reg.MustRegister(metrics.NetworkTCPConnections)
+	reg.MustRegister(metrics.NetworkTCPEvents)
+	reg.MustRegister(metrics.NetworkUDPEvents)
+	reg.MustRegister(metrics.NetworkSockets)
+	reg.MustRegister(metrics.NetworkListenInfo)
+	reg.MustRegister(metrics.NetworkNewListeners)
+	reg.MustRegister(metrics.WirelessInfo)
+	reg.MustRegister(metrics.WirelessConnected)
+	reg.MustRegister(metrics.WirelessSignal)
+	reg.MustRegister(metrics.WirelessLinkQuality)
+	reg.MustRegister(metrics.WirelessBitrate)
+	reg.MustRegister(metrics.WirelessFrequency)
+	reg.MustRegister(metrics.ProbeSuccess)
+	reg.MustRegister(metrics.ProbeDuration)
+	reg.MustRegister(metrics.ProbeTLSCertExpiry)
 	reg.MustRegister(metrics.NetworkRxBytesPerSecond)
 	reg.MustRegister(metrics.NetworkTxBytesPerSecond)
 	reg.MustRegister(metrics.NetworkErrors)
@@ -94,9 +206,13 @@ func (c *Collector) RegisterMetrics(reg prometheus.Registerer) error {
 func (c *Collector) Start(ctx context.Context) error {
 	go mockconfig.Watch(ctx.Done())
 
-	deviceConfig, err := deviceconfig.Read(c.deviceConfigPath)
-	if err != nil {
-		return fmt.Errorf("failed to read device config: %w", err)
+	deviceConfig := c.deviceConfig
+	if deviceConfig == nil {
+		var err error
+		deviceConfig, err = deviceconfig.Read(c.deviceConfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to read device config: %w", err)
+		}
 	}
 	metrics.RecordSNMetrics(deviceConfig)
 
@@ -112,12 +228,29 @@ func (c *Collector) Start(ctx context.Context) error {
 	}
 
 	metrics.RecordBiosInfo()
-	metrics.RecordProccessInfo()
+	metrics.RecordProccessInfo(deviceConfig.Processes)
+	metrics.RecordProcessGroups(deviceConfig.Processes)
+	metrics.RecordSystemdMetrics(deviceConfig.Systemd)
 	metrics.RecordCPUInfo()
+	metrics.RecordCPUStat()
+	metrics.RecordCPUTopology()
+	metrics.RecordHwmonMetrics()
+	metrics.RecordPressureMetrics()
 	metrics.RecordMemoryModuleInfo()
+	metrics.RecordEdacMetrics()
 	metrics.RecordMemoryUsage()
+	metrics.RecordMemoryBreakdown()
+	metrics.StartDiskHealthWorker(deviceConfig.DiskHealth)
 	metrics.RecordDiskUsage()
+	metrics.RecordDiskTemperature()
+	metrics.RecordNvmeMetrics()
+	metrics.RecordRemovableDevices()
 	metrics.RecordNetworkMetrics()
+	metrics.RecordNetworkConfig()
+	metrics.RecordNetstatMetrics()
+	metrics.RecordListeningPorts()
+	metrics.RecordWirelessMetrics()
+	metrics.RecordProbes(deviceConfig.Probes)
 	metrics.RecordGpuInfo()
 	metrics.RecordMotherboardInfo()
 	metrics.RecordSystemMetrics()
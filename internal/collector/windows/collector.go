@@ -17,6 +17,7 @@ import (
 type Collector struct {
 	mockEnabled      bool
 	deviceConfigPath string
+	deviceConfig     *deviceconfig.Config
 }
 
 func New() *Collector {
@@ -33,6 +34,14 @@ func (c *Collector) RegisterMetrics(reg prometheus.Registerer) error {
 
 	c.mockEnabled = mockconfig.IsEnabled()
 
+	deviceConfig, err := deviceconfig.Read(c.deviceConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to read device config: %w", err)
+	}
+	c.deviceConfig = deviceConfig
+
+	metrics.ConfigureDiskLabels(deviceConfig.Disk.LegacyLabels)
+
 	if c.mockEnabled {
 		metrics.LogMockEnabled()
 		reg.MustRegister(
@@ -47,6 +56,7 @@ func (c *Collector) RegisterMetrics(reg prometheus.Registerer) error {
 			metrics.TotalMemory,
 			metrics.UsedMemory,
 			metrics.FreeMemory,
+			metrics.AvailableMemory,
 			metrics.NetworkErrors,
 			metrics.SerialNumberMetric,
 		)
@@ -57,26 +67,47 @@ func (c *Collector) RegisterMetrics(reg prometheus.Registerer) error {
 	reg.MustRegister(metrics.ProccessCount)
 	reg.MustRegister(metrics.ProccessMemoryUsage)
 	reg.MustRegister(metrics.ProccessCPUUsage)
+	reg.MustRegister(metrics.ProcessPidCPUSeconds)
 	reg.MustRegister(metrics.ProcessInstanceCount)
 	reg.MustRegister(metrics.ProcessGroupMemoryWorkingSet)
 	reg.MustRegister(metrics.ProcessGroupMemoryPrivate)
 	reg.MustRegister(metrics.ProcessGroupCPUUsage)
+	reg.MustRegister(metrics.ProcessSeriesDropped)
+	reg.MustRegister(metrics.ProcessGroupSeriesDropped)
+	reg.MustRegister(metrics.NamedProcessGroupProcesses)
+	reg.MustRegister(metrics.NamedProcessGroupCPUSeconds)
+	reg.MustRegister(metrics.NamedProcessGroupResidentMemory)
+	reg.MustRegister(metrics.NamedProcessGroupOpenFDs)
+	reg.MustRegister(metrics.NamedProcessGroupThreads)
+	reg.MustRegister(metrics.NamedProcessGroupReadBytes)
+	reg.MustRegister(metrics.NamedProcessGroupWriteBytes)
+	reg.MustRegister(metrics.NamedProcessGroupContextSwitches)
+	reg.MustRegister(metrics.NamedProcessGroupStarts)
+	reg.MustRegister(metrics.NamedProcessGroupExits)
+	reg.MustRegister(metrics.NamedProcessGroupOldestStart)
+	reg.MustRegister(metrics.ProcessRequiredUp)
+	reg.MustRegister(metrics.ProcessForbiddenPresent)
 	reg.MustRegister(metrics.CpuUsage)
 	reg.MustRegister(metrics.CpuTemperature)
 	reg.MustRegister(metrics.MemoryModuleInfo)
 	reg.MustRegister(metrics.TotalMemory)
 	reg.MustRegister(metrics.UsedMemory)
 	reg.MustRegister(metrics.FreeMemory)
+	reg.MustRegister(metrics.AvailableMemory)
 	reg.MustRegister(metrics.DiskUsage)
 	reg.MustRegister(metrics.DiskUsagePercent)
 	reg.MustRegister(metrics.DiskReadBytes)
 	reg.MustRegister(metrics.DiskWriteBytes)
 	reg.MustRegister(metrics.DiskHealthStatus)
+	reg.MustRegister(metrics.NetworkInterfaceInfo)
 	reg.MustRegister(metrics.NetworkStatus)
 	reg.MustRegister(metrics.NetworkRxBytesPerSecond)
 	reg.MustRegister(metrics.NetworkTxBytesPerSecond)
 	reg.MustRegister(metrics.NetworkErrors)
 	reg.MustRegister(metrics.NetworkDroppedPackets)
+	reg.MustRegister(metrics.ProbeSuccess)
+	reg.MustRegister(metrics.ProbeDuration)
+	reg.MustRegister(metrics.ProbeTLSCertExpiry)
 	reg.MustRegister(metrics.GpuInfo)
 	reg.MustRegister(metrics.GpuMemory)
 	reg.MustRegister(metrics.GpuType)
@@ -93,9 +124,13 @@ func (c *Collector) RegisterMetrics(reg prometheus.Registerer) error {
 func (c *Collector) Start(ctx context.Context) error {
 	go mockconfig.Watch(ctx.Done())
 
-	deviceConfig, err := deviceconfig.Read(c.deviceConfigPath)
-	if err != nil {
-		return fmt.Errorf("failed to read device config: %w", err)
+	deviceConfig := c.deviceConfig
+	if deviceConfig == nil {
+		var err error
+		deviceConfig, err = deviceconfig.Read(c.deviceConfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to read device config: %w", err)
+		}
 	}
 	metrics.RecordSNMetrics(deviceConfig)
 
@@ -111,12 +146,14 @@ func (c *Collector) Start(ctx context.Context) error {
 	}
 
 	metrics.RecordBiosInfo()
-	metrics.RecordProccessInfo()
+	metrics.RecordProccessInfo(deviceConfig.Processes)
+	metrics.RecordProcessGroups(deviceConfig.Processes)
 	metrics.RecordCPUInfo()
 	metrics.RecordMemoryModuleInfo()
 	metrics.RecordMemoryUsage()
 	metrics.RecordDiskUsage()
 	metrics.RecordNetworkMetrics()
+	metrics.RecordProbes(deviceConfig.Probes)
 	metrics.RecordGpuInfo()
 	metrics.RecordMotherboardInfo()
 	metrics.RecordSystemMetrics()
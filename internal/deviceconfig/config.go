@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -15,6 +16,151 @@ type Config struct {
 	SerialNumber string `yaml:"serial_number"`
 	Location     string `yaml:"location"`
 	DeviceTag    string `yaml:"device_tag"`
+
+	Disk       DiskConfig       `yaml:"disk"`
+	DiskHealth DiskHealthConfig `yaml:"disk_health"`
+	Probes     ProbesConfig     `yaml:"probes"`
+	Processes  ProcessesConfig  `yaml:"processes"`
+	Systemd    SystemdConfig    `yaml:"systemd"`
+}
+
+// DiskConfig задаёт параметры дисковых метрик.
+type DiskConfig struct {
+	// LegacyLabels добавляет к единой схеме лейблов прежние лейблы disk/type
+	// на время миграции дашбордов.
+	LegacyLabels bool `yaml:"legacy_labels"`
+}
+
+// DiskHealthConfig задаёт параметры фонового опроса SMART/NVMe.
+type DiskHealthConfig struct {
+	Interval  time.Duration `yaml:"interval"`
+	CachePath string        `yaml:"cache_path"`
+}
+
+const DefaultDiskHealthInterval = 10 * time.Minute
+
+// EffectiveInterval возвращает интервал опроса с учётом значения по умолчанию.
+func (c DiskHealthConfig) EffectiveInterval() time.Duration {
+	if c.Interval <= 0 {
+		return DefaultDiskHealthInterval
+	}
+	if c.Interval < time.Minute {
+		return time.Minute
+	}
+	return c.Interval
+}
+
+// ProbesConfig задаёт список целей для активных проверок доступности.
+type ProbesConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Targets  []ProbeTarget `yaml:"targets"`
+}
+
+// ProbeTarget — одна цель проверки. Module — icmp, tcp или http; Target —
+// хост для icmp, host:port для tcp и URL для http. InsecureSkipVerify отключает
+// проверку сертификата для внутренних сервисов с самоподписанными сертификатами.
+type ProbeTarget struct {
+	Name               string `yaml:"name"`
+	Module             string `yaml:"module"`
+	Target             string `yaml:"target"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+const (
+	DefaultProbeInterval = 30 * time.Second
+	DefaultProbeTimeout  = 5 * time.Second
+)
+
+// EffectiveInterval возвращает интервал проверок с учётом значения по умолчанию.
+func (c ProbesConfig) EffectiveInterval() time.Duration {
+	if c.Interval <= 0 {
+		return DefaultProbeInterval
+	}
+	return c.Interval
+}
+
+// EffectiveTimeout возвращает таймаут одной проверки; он не превышает интервал.
+func (c ProbesConfig) EffectiveTimeout() time.Duration {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+	if interval := c.EffectiveInterval(); timeout > interval {
+		timeout = interval
+	}
+	return timeout
+}
+
+// ProcessesConfig ограничивает набор процессов, для которых публикуются
+// метрики по PID. Include/Exclude и MaxSeries применяются и к групповым
+// метрикам по имени процесса, TopN — только к метрикам по PID.
+type ProcessesConfig struct {
+	Include []ProcessFilter `yaml:"include"`
+	Exclude []ProcessFilter `yaml:"exclude"`
+	// TopN оставляет только N процессов с наибольшим потреблением TopBy
+	// (cpu или memory); 0 — без ограничения.
+	TopN  int    `yaml:"top_n"`
+	TopBy string `yaml:"top_by"`
+	// MaxSeries — жёсткий предел числа процессов с метриками по PID и
+	// отдельно числа имён с групповыми метриками.
+	MaxSeries int `yaml:"max_series"`
+	// Groups — именованные группы процессов с агрегированными метриками.
+	Groups []ProcessGroup `yaml:"groups"`
+	// RequiredProcesses и ForbiddenProcesses — процессы, которые должны быть
+	// запущены (например, антивирус) и которых быть не должно (например,
+	// майнер). Условия задаются так же, как для групп.
+	RequiredProcesses  []ProcessGroup `yaml:"required_processes"`
+	ForbiddenProcesses []ProcessGroup `yaml:"forbidden_processes"`
+}
+
+// ProcessFilter задаёт регулярные выражения для имени, командной строки и
+// пользователя процесса. Фильтр срабатывает, если совпали все заданные поля.
+type ProcessFilter struct {
+	Name    string `yaml:"name"`
+	Cmdline string `yaml:"cmdline"`
+	User    string `yaml:"user"`
+}
+
+// ProcessGroup объединяет процессы приложения под одним именем независимо от
+// PID. Exe сравнивается с именем и путём исполняемого файла, Cgroup — с путём
+// cgroup процесса (только Linux). Процесс попадает в группу, если совпали все
+// заданные поля; процесс учитывается только в первой подходящей группе.
+type ProcessGroup struct {
+	Name    string `yaml:"name"`
+	Exe     string `yaml:"exe"`
+	Cmdline string `yaml:"cmdline"`
+	Cgroup  string `yaml:"cgroup"`
+	User    string `yaml:"user"`
+}
+
+const DefaultProcessMaxSeries = 500
+
+// EffectiveMaxSeries возвращает предел числа процессов с учётом значения по умолчанию.
+func (c ProcessesConfig) EffectiveMaxSeries() int {
+	if c.MaxSeries <= 0 {
+		return DefaultProcessMaxSeries
+	}
+	return c.MaxSeries
+}
+
+// SystemdConfig задаёт набор юнитов systemd, для которых публикуется
+// состояние. Include и Exclude — регулярные выражения по имени юнита
+// (nginx.service); без Include публикуются все юниты типа service.
+type SystemdConfig struct {
+	Include  []string      `yaml:"include"`
+	Exclude  []string      `yaml:"exclude"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+const DefaultSystemdInterval = 30 * time.Second
+
+// EffectiveInterval возвращает интервал опроса systemd с учётом значения по умолчанию.
+func (c SystemdConfig) EffectiveInterval() time.Duration {
+	if c.Interval <= 0 {
+		return DefaultSystemdInterval
+	}
+	return c.Interval
 }
 
 func DefaultPath() string {
@@ -20,12 +20,12 @@ func readDMIField(name string) string {
 }
 
 func RecordBiosInfo() {
-	manufacturer := readDMIField("bios_vendor")
-	version := readDMIField("bios_version")
-	releaseDate := readDMIField("bios_date")
+	manufacturer := dmiField("bios_vendor")
+	version := dmiField("bios_version")
+	releaseDate := dmiField("bios_date")
 
 	if manufacturer == "" && version == "" && releaseDate == "" {
-		log.Printf("BIOS info unavailable from /sys/class/dmi/id")
+		log.Printf("BIOS info unavailable from /sys/class/dmi/id and SMBIOS tables")
 		return
 	}
 
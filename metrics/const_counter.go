@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ConstCounterVec публикует абсолютные значения счётчиков, которые ведёт
+// источник (SMART-лог, sysfs, systemd), как метрики типа counter. В отличие от
+// prometheus.CounterVec значение задаётся целиком, а не приращением, поэтому
+// накопленное до запуска агента не теряется.
+type ConstCounterVec struct {
+	desc       *prometheus.Desc
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]constCounterValue
+}
+
+type constCounterValue struct {
+	labelValues []string
+	value       float64
+}
+
+func newConstCounterVec(name, help string, labelNames []string) *ConstCounterVec {
+	return &ConstCounterVec{
+		desc:       prometheus.NewDesc(name, help, labelNames, nil),
+		labelNames: labelNames,
+		values:     make(map[string]constCounterValue),
+	}
+}
+
+func (v *ConstCounterVec) labelValues(labels prometheus.Labels) []string {
+	values := make([]string, len(v.labelNames))
+	for i, name := range v.labelNames {
+		values[i] = labels[name]
+	}
+	return values
+}
+
+// Set задаёт текущее значение счётчика с набором лейблов labels.
+func (v *ConstCounterVec) Set(labels prometheus.Labels, value float64) {
+	values := v.labelValues(labels)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.values[strings.Join(values, "\xff")] = constCounterValue{labelValues: values, value: value}
+}
+
+// Delete удаляет серию с набором лейблов labels.
+func (v *ConstCounterVec) Delete(labels prometheus.Labels) {
+	key := strings.Join(v.labelValues(labels), "\xff")
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.values, key)
+}
+
+// DeletePartialMatch удаляет все серии, у которых совпадают заданные лейблы.
+func (v *ConstCounterVec) DeletePartialMatch(labels prometheus.Labels) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for key, entry := range v.values {
+		matched := true
+		for i, name := range v.labelNames {
+			if value, ok := labels[name]; ok && entry.labelValues[i] != value {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			delete(v.values, key)
+		}
+	}
+}
+
+// Reset удаляет все серии.
+func (v *ConstCounterVec) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.values = make(map[string]constCounterValue)
+}
+
+// constCounterBatch накапливает значения одного цикла сбора. Commit заменяет
+// ими все серии вектора разом: серии, не попавшие в цикл, удаляются, а scrape
+// во время сбора видит прежние значения, а не пустой вектор.
+type constCounterBatch struct {
+	vec    *ConstCounterVec
+	values map[string]constCounterValue
+}
+
+// Batch начинает новый цикл сбора.
+func (v *ConstCounterVec) Batch() *constCounterBatch {
+	return &constCounterBatch{vec: v, values: make(map[string]constCounterValue)}
+}
+
+// Set задаёт значение счётчика с набором лейблов labels в этом цикле.
+func (b *constCounterBatch) Set(labels prometheus.Labels, value float64) {
+	values := b.vec.labelValues(labels)
+	b.values[strings.Join(values, "\xff")] = constCounterValue{labelValues: values, value: value}
+}
+
+// Commit публикует значения цикла вместо прежних.
+func (b *constCounterBatch) Commit() {
+	b.vec.mu.Lock()
+	defer b.vec.mu.Unlock()
+	b.vec.values = b.values
+}
+
+func (v *ConstCounterVec) Describe(ch chan<- *prometheus.Desc) {
+	ch <- v.desc
+}
+
+func (v *ConstCounterVec) Collect(ch chan<- prometheus.Metric) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, entry := range v.values {
+		ch <- prometheus.MustNewConstMetric(v.desc, prometheus.CounterValue, entry.value, entry.labelValues...)
+	}
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"math"
+	"time"
+)
+
+// counterTracker вычисляет приращения монотонных счётчиков ОС (байты, ошибки,
+// отброшенные пакеты) между опросами. Простое вычитание uint64 при сбросе или
+// переполнении счётчика даёт значение порядка 1.8e19, поэтому трекер различает:
+//
+//   - переполнение счётчика объявленной разрядности (значение перешло через
+//     максимум 32- или 64-битного диапазона);
+//   - сброс счётчика (драйвер или устройство начали счёт заново);
+//   - пересоздание объекта (интерфейс удалён и создан с тем же именем) — по
+//     смене identity, например ifindex.
+//
+// Разрядность по значению не угадывается: 64-битный счётчик с небольшим
+// значением иначе принимается за 32-битный, и сброс превращается в
+// переполнение на ~4e9. Её объявляет вызывающий по типу поля в источнике.
+//
+// Трекер не потокобезопасен и используется из одной горутины сборщика.
+type counterTracker struct {
+	samples map[string]counterSample
+}
+
+// counterWidth — разрядность счётчика в источнике.
+type counterWidth uint8
+
+const (
+	counter32 counterWidth = 32
+	counter64 counterWidth = 64
+)
+
+// max возвращает максимальное значение счётчика разрядности w.
+func (w counterWidth) max() uint64 {
+	if w == counter32 {
+		return math.MaxUint32
+	}
+	return math.MaxUint64
+}
+
+type counterSample struct {
+	value    uint64
+	identity string
+	at       time.Time
+}
+
+// counterObservation — результат наблюдения счётчика. Valid = false для первого
+// наблюдения, когда приращение ещё неизвестно.
+type counterObservation struct {
+	Delta   uint64
+	Elapsed float64
+	Valid   bool
+	Reset   bool
+}
+
+func newCounterTracker() *counterTracker {
+	return &counterTracker{samples: make(map[string]counterSample)}
+}
+
+// Observe запоминает новое значение счётчика key разрядности width и
+// возвращает приращение с предыдущего наблюдения. identity идентифицирует
+// источник счётчика; пустая строка означает, что пересоздание не отслеживается.
+func (t *counterTracker) Observe(key, identity string, value uint64, width counterWidth, now time.Time) counterObservation {
+	prev, ok := t.samples[key]
+	t.samples[key] = counterSample{value: value, identity: identity, at: now}
+	if !ok {
+		return counterObservation{}
+	}
+
+	observation := counterObservation{Valid: true, Elapsed: now.Sub(prev.at).Seconds()}
+	if prev.identity != identity {
+		// новый объект начинает счёт с нуля
+		observation.Delta, observation.Reset = value, true
+		return observation
+	}
+
+	observation.Delta, observation.Reset = counterDelta(prev.value, value, width)
+	return observation
+}
+
+// Prune удаляет счётчики, которые не наблюдались с момента before, чтобы
+// исчезнувшие интерфейсы и диски не накапливались в памяти.
+func (t *counterTracker) Prune(before time.Time) {
+	for key, sample := range t.samples {
+		if sample.at.Before(before) {
+			delete(t.samples, key)
+		}
+	}
+}
+
+// counterDelta возвращает приращение счётчика разрядности width от prev до cur.
+// Уменьшение значения считается переполнением, если prev был в верхней
+// половине диапазона счётчика, иначе — сбросом, после которого счёт начался с
+// нуля и приращение равно cur.
+func counterDelta(prev, cur uint64, width counterWidth) (delta uint64, reset bool) {
+	if cur >= prev {
+		return cur - prev, false
+	}
+
+	limit := width.max()
+	if prev <= limit && cur <= limit && prev > limit/2 {
+		return limit - prev + cur + 1, false
+	}
+
+	return cur, true
+}
+
+// rate возвращает скорость изменения счётчика в единицах в секунду. Для первого
+// наблюдения и нулевого интервала возвращается 0.
+func (o counterObservation) rate() float64 {
+	if !o.Valid || o.Elapsed <= 0 {
+		return 0
+	}
+	return float64(o.Delta) / o.Elapsed
+}
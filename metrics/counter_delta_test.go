@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCounterDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev, cur uint64
+		width     counterWidth
+		wantDelta uint64
+		wantReset bool
+	}{
+		{"growth", 100, 150, counter64, 50, false},
+		{"unchanged", 100, 100, counter32, 0, false},
+		{"32-bit wrap", math.MaxUint32 - 9, 5, counter32, 15, false},
+		{"64-bit wrap", math.MaxUint64 - 9, 5, counter64, 15, false},
+		// небольшой 64-битный счётчик не принимается за 32-битный
+		{"64-bit reset below 32-bit max", math.MaxUint32 - 9, 5, counter64, 5, true},
+		{"32-bit reset", 1000, 10, counter32, 10, true},
+		{"64-bit reset", 1 << 40, 10, counter64, 10, true},
+		// значение вне диапазона 32-битного счётчика может быть только сбросом
+		{"32-bit out of range", 1 << 40, 10, counter32, 10, true},
+	}
+
+	for _, tt := range tests {
+		delta, reset := counterDelta(tt.prev, tt.cur, tt.width)
+		if delta != tt.wantDelta || reset != tt.wantReset {
+			t.Errorf("%s: counterDelta(%d, %d, %d) = %d, %v; want %d, %v",
+				tt.name, tt.prev, tt.cur, tt.width, delta, reset, tt.wantDelta, tt.wantReset)
+		}
+	}
+}
+
+func TestCounterTrackerObserve(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	type step struct {
+		identity string
+		value    uint64
+		width    counterWidth
+		want     counterObservation
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "first sample",
+			steps: []step{
+				{"1", 500, counter64, counterObservation{}},
+			},
+		},
+		{
+			name: "growth",
+			steps: []step{
+				{"1", 500, counter64, counterObservation{}},
+				{"1", 800, counter64, counterObservation{Delta: 300, Elapsed: 10, Valid: true}},
+			},
+		},
+		{
+			name: "32-bit wrap",
+			steps: []step{
+				{"1", math.MaxUint32 - 99, counter32, counterObservation{}},
+				{"1", 100, counter32, counterObservation{Delta: 200, Elapsed: 10, Valid: true}},
+			},
+		},
+		{
+			name: "64-bit wrap",
+			steps: []step{
+				{"1", math.MaxUint64 - 99, counter64, counterObservation{}},
+				{"1", 100, counter64, counterObservation{Delta: 200, Elapsed: 10, Valid: true}},
+			},
+		},
+		{
+			name: "reset",
+			steps: []step{
+				{"1", 5000, counter64, counterObservation{}},
+				{"1", 40, counter64, counterObservation{Delta: 40, Elapsed: 10, Valid: true, Reset: true}},
+				{"1", 90, counter64, counterObservation{Delta: 50, Elapsed: 10, Valid: true}},
+			},
+		},
+		{
+			name: "identity change",
+			steps: []step{
+				{"1", 5000, counter64, counterObservation{}},
+				// пересозданный объект считается с нуля, даже если значение выросло
+				{"2", 7000, counter64, counterObservation{Delta: 7000, Elapsed: 10, Valid: true, Reset: true}},
+				{"2", 7100, counter64, counterObservation{Delta: 100, Elapsed: 10, Valid: true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tracker := newCounterTracker()
+		for i, s := range tt.steps {
+			now := start.Add(time.Duration(i) * 10 * time.Second)
+			got := tracker.Observe("eth0|rx", s.identity, s.value, s.width, now)
+			if got != s.want {
+				t.Errorf("%s: step %d: Observe() = %+v, want %+v", tt.name, i, got, s.want)
+			}
+		}
+	}
+}
+
+func TestCounterObservationRate(t *testing.T) {
+	tests := []struct {
+		observation counterObservation
+		want        float64
+	}{
+		{counterObservation{}, 0},
+		{counterObservation{Delta: 100, Elapsed: 0, Valid: true}, 0},
+		{counterObservation{Delta: 100, Elapsed: 4, Valid: true}, 25},
+	}
+
+	for _, tt := range tests {
+		if got := tt.observation.rate(); got != tt.want {
+			t.Errorf("%+v.rate() = %v, want %v", tt.observation, got, tt.want)
+		}
+	}
+}
+
+func TestCounterTrackerPrune(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tracker := newCounterTracker()
+
+	tracker.Observe("eth0|rx", "", 100, counter64, start)
+	tracker.Observe("eth1|rx", "", 100, counter64, start.Add(time.Minute))
+
+	tracker.Prune(start.Add(30 * time.Second))
+
+	if _, ok := tracker.samples["eth0|rx"]; ok {
+		t.Error("stale counter eth0|rx was not pruned")
+	}
+	if _, ok := tracker.samples["eth1|rx"]; !ok {
+		t.Error("fresh counter eth1|rx was pruned")
+	}
+
+	// после удаления счётчик начинается заново, без приращения от старого значения
+	if got := tracker.Observe("eth0|rx", "", 500, counter64, start.Add(2*time.Minute)); got.Valid {
+		t.Errorf("Observe() after Prune = %+v, want first sample", got)
+	}
+}
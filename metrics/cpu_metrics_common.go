@@ -19,3 +19,85 @@ var (
 		[]string{"sensor"},
 	)
 )
+
+var (
+	// абсолютные значения с загрузки из /proc/stat
+	CpuSecondsTotal = newConstCounterVec(
+		"cpu_seconds_total",
+		"Seconds each CPU spent in each mode since boot (user, nice, system, idle, iowait, irq, softirq, steal)",
+		[]string{"cpu", "mode"},
+	)
+
+	ContextSwitches = newConstCounterVec(
+		"context_switches_total",
+		"Total number of context switches since boot",
+		nil,
+	)
+
+	Interrupts = newConstCounterVec(
+		"interrupts_total",
+		"Total number of serviced interrupts since boot",
+		nil,
+	)
+
+	Forks = newConstCounterVec(
+		"forks_total",
+		"Total number of forks (created processes and threads) since boot",
+		nil,
+	)
+
+	LoadAverage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "load_average",
+			Help: "System load average over the period (1m, 5m, 15m)",
+		},
+		[]string{"period"},
+	)
+
+	ProcsRunning = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "procs_running",
+			Help: "Number of processes in runnable state",
+		},
+	)
+
+	ProcsBlocked = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "procs_blocked",
+			Help: "Number of processes blocked waiting for I/O",
+		},
+	)
+)
+
+var (
+	CpuFrequency = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cpu_frequency_hertz",
+			Help: "CPU core frequency in hertz (type = current/min/max)",
+		},
+		[]string{"cpu", "type"},
+	)
+
+	// счётчики thermal_throttle ядра ведутся с загрузки
+	CpuThermalThrottle = newConstCounterVec(
+		"cpu_thermal_throttle_total",
+		"Number of thermal throttling events since boot (scope = core with id of the logical CPU, or package with id of the socket)",
+		[]string{"scope", "id"},
+	)
+
+	CpuInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cpu_info",
+			Help: "Logical CPU topology and capabilities: socket, core, thread, model, microcode, virtualization (value is always 1)",
+		},
+		[]string{"cpu", "socket", "core", "thread", "model", "microcode", "virtualization", "hypervisor"},
+	)
+
+	CpuSocketInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cpu_socket_info",
+			Help: "Populated processor sockets from SMBIOS: socket designation, manufacturer, model, max speed, core and thread count (value is always 1)",
+		},
+		[]string{"socket", "manufacturer", "model", "max_speed_mhz", "cores", "threads"},
+	)
+)
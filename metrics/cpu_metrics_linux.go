@@ -26,6 +26,19 @@ func RecordCPUInfo() {
 			log.Printf("failed to query cpu info: %v", err)
 		}
 
+		// cpu.Info() может вернуть меньше записей, чем логических CPU (например,
+		// одну запись на сокет), поэтому модель берётся по номеру processor
+		models := cpuModelNames()
+		for _, info := range cpuInfo {
+			key := fmt.Sprintf("%d", info.CPU)
+			if models[key] == "" && info.ModelName != "" {
+				if models == nil {
+					models = make(map[string]string)
+				}
+				models[key] = info.ModelName
+			}
+		}
+
 		ticker := time.NewTicker(5 * time.Second)
 		defer ticker.Stop()
 
@@ -36,12 +49,11 @@ func RecordCPUInfo() {
 			} else {
 				logicalCores := fmt.Sprintf("%d", runtime.NumCPU())
 				for idx, pct := range percentages {
-					model := "unknown"
-					if idx < len(cpuInfo) {
-						model = cpuInfo[idx].ModelName
-					} else if len(cpuInfo) > 0 {
+					model := models[fmt.Sprintf("%d", idx)]
+					if model == "" && len(cpuInfo) > 0 {
 						model = cpuInfo[0].ModelName
 					}
+					model = orUnknown(model)
 
 					CpuUsage.With(prometheus.Labels{
 						"core":          fmt.Sprintf("core_%d", idx),
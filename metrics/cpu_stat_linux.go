@@ -0,0 +1,137 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// userHZ — единица времени в /proc/stat (USER_HZ). На всех поддерживаемых
+// архитектурах Linux она равна 100.
+const userHZ = 100
+
+// порядок полей строки cpuN в /proc/stat; guest и guest_nice уже учтены в
+// user и nice, поэтому не публикуются отдельно
+var cpuStatModes = []string{"user", "nice", "system", "idle", "iowait", "irq", "softirq", "steal"}
+
+type procStat struct {
+	CPUs         map[string][]uint64
+	ContextSw    uint64
+	Interrupts   uint64
+	Forks        uint64
+	ProcsRunning float64
+	ProcsBlocked float64
+}
+
+func RecordCPUStat() {
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+
+		ticks := make(map[string]uint64)
+
+		for {
+			if data, err := os.ReadFile("/proc/stat"); err == nil {
+				recordProcStat(parseProcStat(string(data)), ticks)
+			}
+
+			if data, err := os.ReadFile("/proc/loadavg"); err == nil {
+				if loads, ok := parseLoadAvg(string(data)); ok {
+					for i, period := range []string{"1m", "5m", "15m"} {
+						LoadAverage.With(prometheus.Labels{"period": period}).Set(loads[i])
+					}
+				}
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+// recordProcStat публикует время CPU как абсолютные значения /proc/stat,
+// накопленные с загрузки. Ядро может вернуть меньшее значение iowait и idle,
+// чем в прошлый раз (так учитывается время простоя на NO_HZ-ядрах), поэтому в
+// ticks хранится максимум по каждому режиму и счётчик никогда не уменьшается.
+// Число переключений контекста, прерываний и fork тоже публикуется как есть.
+func recordProcStat(stat procStat, ticks map[string]uint64) {
+	for cpu, values := range stat.CPUs {
+		for i, mode := range cpuStatModes {
+			if i >= len(values) {
+				break
+			}
+			key := cpu + "|" + mode
+			if values[i] > ticks[key] {
+				ticks[key] = values[i]
+			}
+			CpuSecondsTotal.Set(prometheus.Labels{"cpu": cpu, "mode": mode}, float64(ticks[key])/userHZ)
+		}
+	}
+
+	ContextSwitches.Set(nil, float64(stat.ContextSw))
+	Interrupts.Set(nil, float64(stat.Interrupts))
+	Forks.Set(nil, float64(stat.Forks))
+	ProcsRunning.Set(stat.ProcsRunning)
+	ProcsBlocked.Set(stat.ProcsBlocked)
+}
+
+// parseProcStat разбирает /proc/stat. Агрегированная строка "cpu" пропускается:
+// её можно получить суммой по ядрам.
+func parseProcStat(data string) procStat {
+	stat := procStat{CPUs: make(map[string][]uint64)}
+
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(fields[0], "cpu") && fields[0] != "cpu":
+			values := make([]uint64, 0, len(fields)-1)
+			for _, field := range fields[1:] {
+				value, err := strconv.ParseUint(field, 10, 64)
+				if err != nil {
+					break
+				}
+				values = append(values, value)
+			}
+			stat.CPUs[strings.TrimPrefix(fields[0], "cpu")] = values
+		case fields[0] == "ctxt":
+			stat.ContextSw, _ = strconv.ParseUint(fields[1], 10, 64)
+		case fields[0] == "intr":
+			// первое значение — общее число прерываний, далее по линиям
+			stat.Interrupts, _ = strconv.ParseUint(fields[1], 10, 64)
+		case fields[0] == "processes":
+			stat.Forks, _ = strconv.ParseUint(fields[1], 10, 64)
+		case fields[0] == "procs_running":
+			stat.ProcsRunning, _ = strconv.ParseFloat(fields[1], 64)
+		case fields[0] == "procs_blocked":
+			stat.ProcsBlocked, _ = strconv.ParseFloat(fields[1], 64)
+		}
+	}
+
+	return stat
+}
+
+// parseLoadAvg разбирает первые три поля /proc/loadavg: "0.52 0.58 0.59 1/467 12345".
+func parseLoadAvg(data string) ([3]float64, bool) {
+	var loads [3]float64
+
+	fields := strings.Fields(data)
+	if len(fields) < 3 {
+		return loads, false
+	}
+	for i := range loads {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return loads, false
+		}
+		loads[i] = value
+	}
+	return loads, true
+}
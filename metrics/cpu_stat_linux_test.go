@@ -0,0 +1,86 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testdata/proc/stat и loadavg — /proc двухъядерной виртуальной машины.
+
+func TestParseProcStat(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "proc", "stat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := parseProcStat(string(data))
+	want := procStat{
+		CPUs: map[string][]uint64{
+			"0": {2354, 178, 291, 1849533, 1203, 0, 98, 0, 0, 0},
+			"1": {2351, 178, 293, 1849643, 1174, 0, 26, 0, 0, 0},
+		},
+		ContextSw:    4923104,
+		Interrupts:   1813429,
+		Forks:        31877,
+		ProcsRunning: 2,
+		ProcsBlocked: 1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProcStat() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseLoadAvg(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "proc", "loadavg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := parseLoadAvg(string(data)); !ok || got != [3]float64{0.52, 0.58, 0.59} {
+		t.Errorf("parseLoadAvg() = %v, %v", got, ok)
+	}
+	if _, ok := parseLoadAvg("0.52 abc 0.59 2/467 31876"); ok {
+		t.Error("parseLoadAvg(malformed) succeeded")
+	}
+}
+
+func TestRecordProcStat(t *testing.T) {
+	CpuSecondsTotal.Reset()
+
+	ticks := make(map[string]uint64)
+	stat := procStat{
+		CPUs:       map[string][]uint64{"0": {250, 0, 100, 5000, 40, 0, 10, 0}},
+		ContextSw:  4923104,
+		Interrupts: 1813429,
+		Forks:      31877,
+	}
+	recordProcStat(stat, ticks)
+
+	// на NO_HZ-ядре iowait может уменьшиться, счётчик при этом не убывает
+	stat.CPUs["0"] = []uint64{260, 0, 100, 5100, 30, 0, 10, 0}
+	stat.ContextSw, stat.Interrupts, stat.Forks = 4923500, 1813600, 31880
+	recordProcStat(stat, ticks)
+
+	// значения с загрузки публикуются целиком, а не приращениями с запуска агента
+	want := []string{
+		"context_switches_total{} 4.9235e+06",
+		"cpu_seconds_total{cpu=0,mode=idle} 51",
+		"cpu_seconds_total{cpu=0,mode=iowait} 0.4",
+		"cpu_seconds_total{cpu=0,mode=irq} 0",
+		"cpu_seconds_total{cpu=0,mode=nice} 0",
+		"cpu_seconds_total{cpu=0,mode=softirq} 0.1",
+		"cpu_seconds_total{cpu=0,mode=steal} 0",
+		"cpu_seconds_total{cpu=0,mode=system} 1",
+		"cpu_seconds_total{cpu=0,mode=user} 2.6",
+		"forks_total{} 31880",
+		"interrupts_total{} 1.8136e+06",
+	}
+	if got := gatherSeries(t, ContextSwitches, CpuSecondsTotal, Forks, Interrupts); !reflect.DeepEqual(got, want) {
+		t.Errorf("series:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
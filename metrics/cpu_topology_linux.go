@@ -0,0 +1,296 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const cpuSysfsRoot = "/sys/devices/system/cpu"
+
+var cpuDirPattern = regexp.MustCompile(`^cpu[0-9]+$`)
+
+// procCPUInfo — запись одного логического процессора из /proc/cpuinfo.
+type procCPUInfo struct {
+	Model     string
+	Microcode string
+	Flags     map[string]bool
+}
+
+type cpuTopology struct {
+	CPU    string
+	Socket string
+	Core   string
+	Thread string
+}
+
+// cpuTopologySeries — серии логических CPU. Серии отключённых (offline) CPU и
+// прежние значения лейблов после обновления микрокода пропадают при Flush.
+type cpuTopologySeries struct {
+	info      *gaugeSeries
+	frequency *gaugeSeries
+}
+
+func RecordCPUTopology() {
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		recordCPUSockets()
+
+		series := &cpuTopologySeries{
+			info:      newGaugeSeries(CpuInfo),
+			frequency: newGaugeSeries(CpuFrequency),
+		}
+
+		for {
+			cpuinfo := map[string]procCPUInfo{}
+			if data, err := os.ReadFile("/proc/cpuinfo"); err == nil {
+				cpuinfo = parseProcCPUInfo(string(data))
+			}
+
+			recordCPUs(series, cpuSysfsRoot, cpuinfo)
+			<-ticker.C
+		}
+	}()
+}
+
+// recordCPUs публикует топологию, частоту и счётчики термотроттлинга всех
+// логических CPU из sysfs root.
+func recordCPUs(series *cpuTopologySeries, root string, cpuinfo map[string]procCPUInfo) {
+	throttle := CpuThermalThrottle.Batch()
+	seenPackages := make(map[string]bool)
+
+	for _, cpu := range listCPUs(root) {
+		topology := readCPUTopology(root, cpu)
+		info := cpuinfo[cpu]
+
+		series.info.Set(prometheus.Labels{
+			"cpu":            cpu,
+			"socket":         orUnknown(topology.Socket),
+			"core":           orUnknown(topology.Core),
+			"thread":         orUnknown(topology.Thread),
+			"model":          orUnknown(info.Model),
+			"microcode":      orUnknown(info.Microcode),
+			"virtualization": cpuVirtualization(info.Flags),
+			"hypervisor":     strconv.FormatBool(info.Flags["hypervisor"]),
+		}, 1)
+
+		recordCPUFrequency(series.frequency, root, cpu)
+
+		dir := filepath.Join(root, "cpu"+cpu, "thermal_throttle")
+		if value, err := strconv.ParseUint(readSysfsValue(filepath.Join(dir, "core_throttle_count")), 10, 64); err == nil {
+			throttle.Set(prometheus.Labels{"scope": "core", "id": cpu}, float64(value))
+		}
+		// счётчик пакета одинаков для всех его CPU, учитываем его один раз на сокет
+		if value, err := strconv.ParseUint(readSysfsValue(filepath.Join(dir, "package_throttle_count")), 10, 64); err == nil && topology.Socket != "" && !seenPackages[topology.Socket] {
+			seenPackages[topology.Socket] = true
+			throttle.Set(prometheus.Labels{"scope": "package", "id": topology.Socket}, float64(value))
+		}
+	}
+
+	series.info.Flush()
+	series.frequency.Flush()
+	throttle.Commit()
+}
+
+// recordCPUSockets публикует заполненные процессорные сокеты из SMBIOS (type 4).
+func recordCPUSockets() {
+	tables, err := loadSMBIOS()
+	if err != nil {
+		return
+	}
+
+	for _, proc := range tables.processors() {
+		CpuSocketInfo.With(prometheus.Labels{
+			"socket":        orUnknown(proc.Socket),
+			"manufacturer":  orUnknown(proc.Manufacturer),
+			"model":         orUnknown(proc.Version),
+			"max_speed_mhz": strconv.Itoa(int(proc.MaxSpeedMHz)),
+			"cores":         strconv.Itoa(int(proc.CoreCount)),
+			"threads":       strconv.Itoa(int(proc.ThreadCount)),
+		}).Set(1)
+	}
+}
+
+// listCPUs возвращает номера логических CPU из sysfs в порядке возрастания.
+func listCPUs(root string) []string {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var ids []int
+	for _, entry := range entries {
+		if !cpuDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+		if id, err := strconv.Atoi(strings.TrimPrefix(entry.Name(), "cpu")); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	cpus := make([]string, 0, len(ids))
+	for _, id := range ids {
+		cpus = append(cpus, strconv.Itoa(id))
+	}
+	return cpus
+}
+
+// readCPUTopology читает сокет и ядро логического CPU; thread — порядковый номер
+// CPU среди SMT-соседей своего ядра.
+func readCPUTopology(root, cpu string) cpuTopology {
+	dir := filepath.Join(root, "cpu"+cpu, "topology")
+	topology := cpuTopology{
+		CPU:    cpu,
+		Socket: readSysfsValue(filepath.Join(dir, "physical_package_id")),
+		Core:   readSysfsValue(filepath.Join(dir, "core_id")),
+	}
+
+	id, err := strconv.Atoi(cpu)
+	if err != nil {
+		return topology
+	}
+	for idx, sibling := range parseCPUList(readSysfsValue(filepath.Join(dir, "thread_siblings_list"))) {
+		if sibling == id {
+			topology.Thread = strconv.Itoa(idx)
+			break
+		}
+	}
+	return topology
+}
+
+// parseCPUList разбирает список CPU в формате ядра: "0-3,8,10-11".
+func parseCPUList(value string) []int {
+	var result []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(to); err != nil {
+				continue
+			}
+		}
+		for id := start; id <= end; id++ {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func recordCPUFrequency(series *gaugeSeries, root, cpu string) {
+	dir := filepath.Join(root, "cpu"+cpu, "cpufreq")
+
+	current := readSysfsValue(filepath.Join(dir, "scaling_cur_freq"))
+	if current == "" {
+		current = readSysfsValue(filepath.Join(dir, "cpuinfo_cur_freq"))
+	}
+
+	for typ, raw := range map[string]string{
+		"current": current,
+		"min":     readSysfsValue(filepath.Join(dir, "cpuinfo_min_freq")),
+		"max":     readSysfsValue(filepath.Join(dir, "cpuinfo_max_freq")),
+	} {
+		// значения cpufreq указаны в кГц
+		if khz, err := strconv.ParseFloat(raw, 64); err == nil {
+			series.Set(prometheus.Labels{"cpu": cpu, "type": typ}, khz*1000)
+		}
+	}
+}
+
+// parseProcCPUInfo разбирает /proc/cpuinfo в записи по номеру processor.
+// На ARM модель процессора указывается не во всех записях, поэтому пустые
+// значения заполняются из первой записи, где они есть.
+func parseProcCPUInfo(data string) map[string]procCPUInfo {
+	result := make(map[string]procCPUInfo)
+	var fallback procCPUInfo
+
+	for _, block := range strings.Split(data, "\n\n") {
+		processor := ""
+		info := procCPUInfo{Flags: map[string]bool{}}
+
+		for _, line := range strings.Split(block, "\n") {
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+			switch key {
+			case "processor":
+				processor = value
+			case "model name", "Processor":
+				info.Model = value
+			case "microcode":
+				info.Microcode = value
+			case "flags", "Features":
+				for _, flag := range strings.Fields(value) {
+					info.Flags[flag] = true
+				}
+			}
+		}
+
+		if fallback.Model == "" && info.Model != "" {
+			fallback = info
+		}
+		if processor != "" {
+			result[processor] = info
+		}
+	}
+
+	for processor, info := range result {
+		if info.Model == "" {
+			info.Model = fallback.Model
+		}
+		if info.Microcode == "" {
+			info.Microcode = fallback.Microcode
+		}
+		result[processor] = info
+	}
+
+	return result
+}
+
+// cpuVirtualization определяет аппаратную поддержку виртуализации по флагам CPU.
+func cpuVirtualization(flags map[string]bool) string {
+	switch {
+	case flags["vmx"]:
+		return "vmx"
+	case flags["svm"]:
+		return "svm"
+	default:
+		return "none"
+	}
+}
+
+// cpuModelNames возвращает модель каждого логического CPU по номеру processor.
+func cpuModelNames() map[string]string {
+	data, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return nil
+	}
+
+	models := make(map[string]string)
+	for processor, info := range parseProcCPUInfo(string(data)) {
+		models[processor] = info.Model
+	}
+	return models
+}
@@ -0,0 +1,88 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeCPUSysfs создаёт каталог cpuN в фиктивном /sys/devices/system/cpu.
+func writeCPUSysfs(t *testing.T, root, cpu, core, siblings, coreThrottle, packageThrottle string) {
+	t.Helper()
+
+	dir := filepath.Join(root, "cpu"+cpu)
+	writeSysfsFiles(t, filepath.Join(dir, "topology"), map[string]string{
+		"physical_package_id":  "0",
+		"core_id":              core,
+		"thread_siblings_list": siblings,
+	})
+	writeSysfsFiles(t, filepath.Join(dir, "cpufreq"), map[string]string{
+		"scaling_cur_freq": "2400000",
+		"cpuinfo_min_freq": "800000",
+		"cpuinfo_max_freq": "3600000",
+	})
+	writeSysfsFiles(t, filepath.Join(dir, "thermal_throttle"), map[string]string{
+		"core_throttle_count":    coreThrottle,
+		"package_throttle_count": packageThrottle,
+	})
+}
+
+func TestRecordCPUs(t *testing.T) {
+	CpuInfo.Reset()
+	CpuFrequency.Reset()
+	CpuThermalThrottle.Reset()
+
+	root := t.TempDir()
+	writeCPUSysfs(t, root, "0", "0", "0,2", "12", "40")
+	writeCPUSysfs(t, root, "2", "0", "0,2", "3", "40")
+	cpuinfo := map[string]procCPUInfo{
+		"0": {Model: "Xeon", Microcode: "0xf0", Flags: map[string]bool{"vmx": true}},
+		"2": {Model: "Xeon", Microcode: "0xf0", Flags: map[string]bool{"vmx": true}},
+	}
+
+	series := &cpuTopologySeries{info: newGaugeSeries(CpuInfo), frequency: newGaugeSeries(CpuFrequency)}
+	gather := func() []string {
+		return gatherSeries(t, CpuInfo, CpuFrequency, CpuThermalThrottle)
+	}
+
+	// счётчики, накопленные до запуска агента, видны сразу
+	recordCPUs(series, root, cpuinfo)
+	want := []string{
+		"cpu_frequency_hertz{cpu=0,type=current} 2.4e+09",
+		"cpu_frequency_hertz{cpu=0,type=max} 3.6e+09",
+		"cpu_frequency_hertz{cpu=0,type=min} 8e+08",
+		"cpu_frequency_hertz{cpu=2,type=current} 2.4e+09",
+		"cpu_frequency_hertz{cpu=2,type=max} 3.6e+09",
+		"cpu_frequency_hertz{cpu=2,type=min} 8e+08",
+		"cpu_info{core=0,cpu=0,hypervisor=false,microcode=0xf0,model=Xeon,socket=0,thread=0,virtualization=vmx} 1",
+		"cpu_info{core=0,cpu=2,hypervisor=false,microcode=0xf0,model=Xeon,socket=0,thread=1,virtualization=vmx} 1",
+		"cpu_thermal_throttle_total{id=0,scope=core} 12",
+		"cpu_thermal_throttle_total{id=0,scope=package} 40",
+		"cpu_thermal_throttle_total{id=2,scope=core} 3",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("first cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// cpu2 переведён в offline, микрокод обновлён: прежние серии удаляются
+	if err := os.RemoveAll(filepath.Join(root, "cpu2")); err != nil {
+		t.Fatal(err)
+	}
+	cpuinfo["0"] = procCPUInfo{Model: "Xeon", Microcode: "0xf1", Flags: map[string]bool{"vmx": true}}
+	recordCPUs(series, root, cpuinfo)
+	want = []string{
+		"cpu_frequency_hertz{cpu=0,type=current} 2.4e+09",
+		"cpu_frequency_hertz{cpu=0,type=max} 3.6e+09",
+		"cpu_frequency_hertz{cpu=0,type=min} 8e+08",
+		"cpu_info{core=0,cpu=0,hypervisor=false,microcode=0xf1,model=Xeon,socket=0,thread=0,virtualization=vmx} 1",
+		"cpu_thermal_throttle_total{id=0,scope=core} 12",
+		"cpu_thermal_throttle_total{id=0,scope=package} 40",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("second cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,302 @@
+//go:build linux
+
+package metrics
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"node_exporter_custom/internal/deviceconfig"
+	"node_exporter_custom/registryutil"
+)
+
+const diskHealthCacheFile = "disk_health.json"
+
+// diskHealthState — последнее успешно полученное состояние диска.
+type diskHealthState struct {
+	Status      string                  `json:"status"`
+	CheckedAt   time.Time               `json:"checked_at"`
+	Temperature *diskTemperatureReading `json:"temperature,omitempty"`
+}
+
+type diskHealthSnapshot struct {
+	Disks map[string]diskHealthState `json:"disks"`
+	Nvme  map[string]nvmeSmartLog    `json:"nvme"`
+}
+
+var (
+	diskHealthCache = diskHealthSnapshot{
+		Disks: map[string]diskHealthState{},
+		Nvme:  map[string]nvmeSmartLog{},
+	}
+	diskHealthMu        sync.RWMutex
+	diskHealthCachePath string
+	diskHealthInterval  = deviceconfig.DefaultDiskHealthInterval
+)
+
+// StartDiskHealthWorker запускает фоновый опрос SMART/NVMe. Результаты кешируются
+// в памяти и на диске, поэтому сбор остальных дисковых метрик не блокируется
+// вызовами smartctl/nvme, а после рестарта доступно последнее известное состояние.
+func StartDiskHealthWorker(cfg deviceconfig.DiskHealthConfig) {
+	diskHealthCachePath = cfg.CachePath
+	if diskHealthCachePath == "" {
+		diskHealthCachePath = filepath.Join(registryutil.StateDir(), diskHealthCacheFile)
+	}
+
+	if err := loadDiskHealthCache(diskHealthCachePath); err != nil && !os.IsNotExist(err) {
+		log.Printf("failed to load disk health cache %s: %v", diskHealthCachePath, err)
+	}
+
+	interval := cfg.EffectiveInterval()
+	diskHealthMu.Lock()
+	diskHealthInterval = interval
+	diskHealthMu.Unlock()
+	log.Printf("disk health worker started, interval %s", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			pollDiskHealth()
+
+			if err := saveDiskHealthCache(diskHealthCachePath); err != nil {
+				log.Printf("failed to persist disk health cache: %v", err)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+func pollDiskHealth() {
+	metadata := loadDiskMetadata()
+
+	for baseName := range metadata {
+		status := queryDiskHealth(baseName)
+
+		var temperature *diskTemperatureReading
+		if _, ok := readDiskTemperatureHwmon(baseName); !ok {
+			if reading := readDiskTemperatureSmart(baseName); reading.HasCurrent {
+				reading.CheckedAt = time.Now()
+				temperature = &reading
+			}
+		}
+
+		diskHealthMu.Lock()
+		state := diskHealthCache.Disks[baseName]
+		if status != "" {
+			state.Status = status
+			state.CheckedAt = time.Now()
+		}
+		if temperature != nil {
+			state.Temperature = temperature
+		}
+		if status != "" || temperature != nil {
+			diskHealthCache.Disks[baseName] = state
+		}
+		diskHealthMu.Unlock()
+	}
+
+	for _, ctrl := range discoverNvmeControllers(nvmeSysfsRoot) {
+		smartLog, err := queryNvmeSmartLog("/dev/" + ctrl.Name)
+		if err != nil {
+			continue
+		}
+
+		diskHealthMu.Lock()
+		diskHealthCache.Nvme[ctrl.Name] = smartLog
+		diskHealthMu.Unlock()
+	}
+}
+
+func loadDiskHealthCache(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var snapshot diskHealthSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	diskHealthMu.Lock()
+	defer diskHealthMu.Unlock()
+	for name, state := range snapshot.Disks {
+		diskHealthCache.Disks[name] = state
+	}
+	for name, smartLog := range snapshot.Nvme {
+		diskHealthCache.Nvme[name] = smartLog
+	}
+	return nil
+}
+
+func saveDiskHealthCache(path string) error {
+	diskHealthMu.RLock()
+	data, err := json.MarshalIndent(diskHealthCache, "", "  ")
+	diskHealthMu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// diskHealthStatus возвращает последнее известное состояние диска без запуска
+// внешних утилит.
+func diskHealthStatus(base string) string {
+	diskHealthMu.RLock()
+	state, ok := diskHealthCache.Disks[base]
+	diskHealthMu.RUnlock()
+
+	if !ok || state.Status == "" {
+		return "unknown"
+	}
+	return state.Status
+}
+
+func recordDiskHealthCheckAge(series *gaugeSeries, base string, labelSet diskLabelSet) {
+	diskHealthMu.RLock()
+	state, ok := diskHealthCache.Disks[base]
+	diskHealthMu.RUnlock()
+
+	if !ok || state.CheckedAt.IsZero() {
+		return
+	}
+
+	series.Set(labelSet.labels(nil, prometheus.Labels{"disk": labelSet.Device}), time.Since(state.CheckedAt).Seconds())
+}
+
+// cachedDiskTemperature возвращает температуру, полученную воркером через
+// SMART. Показания старше двух интервалов опроса (загруженные из кеша после
+// рестарта или оставшиеся от зависшего воркера) не выдаются за текущие.
+func cachedDiskTemperature(base string, now time.Time) (diskTemperatureReading, bool) {
+	diskHealthMu.RLock()
+	defer diskHealthMu.RUnlock()
+
+	state, ok := diskHealthCache.Disks[base]
+	if !ok || state.Temperature == nil {
+		return diskTemperatureReading{}, false
+	}
+	if now.Sub(state.Temperature.CheckedAt) > 2*diskHealthInterval {
+		return diskTemperatureReading{}, false
+	}
+	return *state.Temperature, true
+}
+
+func cachedNvmeSmartLog(controller string) (nvmeSmartLog, bool) {
+	diskHealthMu.RLock()
+	defer diskHealthMu.RUnlock()
+
+	smartLog, ok := diskHealthCache.Nvme[controller]
+	return smartLog, ok
+}
+
+func queryDiskHealth(base string) string {
+	if strings.HasPrefix(base, "nvme") {
+		controller := nvmeControllerName(base)
+
+		if status := runNvmeCLI("/dev/" + controller); status != "" {
+			return status
+		}
+
+		if status := runSmartctl([]string{"-H", "-d", "nvme", "/dev/" + controller}); status != "" {
+			return status
+		}
+	}
+
+	return runSmartctl([]string{"-H", "/dev/" + base})
+}
+
+// Биты кода выхода smartctl (man smartctl, EXIT STATUS).
+const (
+	smartctlExitCommandLine = 1 << 0
+	smartctlExitOpenFailed  = 1 << 1
+	smartctlExitDiskFailing = 1 << 3
+	smartctlExitPrefail     = 1 << 4
+)
+
+func runSmartctl(args []string) string {
+	cmd := exec.Command("smartctl", args...)
+	output, err := cmd.CombinedOutput()
+
+	exitCode := 0
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return ""
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	return smartctlHealthStatus(output, exitCode)
+}
+
+// smartctlHealthStatus определяет состояние диска по выводу "smartctl -H" и
+// коду выхода. Код выхода — битовая маска: ненулевой код не означает ошибку
+// запуска, а бит 3 ("DISK FAILING") — главный признак неисправного диска.
+func smartctlHealthStatus(output []byte, exitCode int) string {
+	if exitCode < 0 || exitCode&(smartctlExitCommandLine|smartctlExitOpenFailed) != 0 {
+		return ""
+	}
+	if exitCode&smartctlExitDiskFailing != 0 {
+		return "unhealthy"
+	}
+
+	lower := strings.ToLower(string(output))
+	switch {
+	case strings.Contains(lower, "passed"):
+		if exitCode&smartctlExitPrefail != 0 {
+			return "warning"
+		}
+		return "healthy"
+	case strings.Contains(lower, "warning"), strings.Contains(lower, "prefail"), strings.Contains(lower, "degrad"):
+		return "warning"
+	case strings.Contains(lower, "fail"):
+		return "unhealthy"
+	default:
+		return "unknown"
+	}
+}
+
+func runNvmeCLI(device string) string {
+	cmd := exec.Command("nvme", "smart-log", device)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "critical_warning") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 {
+				value := fields[len(fields)-1]
+				value = strings.TrimPrefix(value, "0x")
+				if value == "0" {
+					return "healthy"
+				}
+				return "warning"
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,69 @@
+//go:build linux
+
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSmartctlHealthStatus(t *testing.T) {
+	const passed = "SMART overall-health self-assessment test result: PASSED\n"
+	const failed = "SMART overall-health self-assessment test result: FAILED!\nDrive failure expected in less than 24 hours. SAVE ALL DATA.\n"
+
+	tests := []struct {
+		name     string
+		output   string
+		exitCode int
+		want     string
+	}{
+		{"passed", passed, 0, "healthy"},
+		// ошибки в журнале (бит 6) не влияют на оценку
+		{"passed with error log", passed, 1 << 6, "healthy"},
+		{"prefail attribute at threshold", passed, 1 << 4, "warning"},
+		{"disk failing", failed, 1 << 3, "unhealthy"},
+		// бит 3 важнее текста, даже если вывод не удалось разобрать
+		{"disk failing without verdict", "", 1<<3 | 1<<2, "unhealthy"},
+		{"failed verdict", failed, 0, "unhealthy"},
+		{"device open failed", "Smartctl open device: /dev/sdz failed: No such device\n", 1 << 1, ""},
+		{"bad command line", "", 1 << 0, ""},
+		{"no verdict", "SMART support is: Unavailable\n", 1 << 2, "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := smartctlHealthStatus([]byte(tt.output), tt.exitCode); got != tt.want {
+			t.Errorf("%s: smartctlHealthStatus() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCachedDiskTemperatureAge(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	diskHealthMu.Lock()
+	saved, savedInterval := diskHealthCache.Disks, diskHealthInterval
+	diskHealthInterval = 10 * time.Minute
+	diskHealthCache.Disks = map[string]diskHealthState{
+		"sda": {Temperature: &diskTemperatureReading{Current: 35, HasCurrent: true, CheckedAt: now.Add(-15 * time.Minute)}},
+		"sdb": {Temperature: &diskTemperatureReading{Current: 41, HasCurrent: true, CheckedAt: now.Add(-3 * 24 * time.Hour)}},
+		"sdc": {Status: "healthy", CheckedAt: now},
+	}
+	diskHealthMu.Unlock()
+
+	defer func() {
+		diskHealthMu.Lock()
+		diskHealthCache.Disks, diskHealthInterval = saved, savedInterval
+		diskHealthMu.Unlock()
+	}()
+
+	if reading, ok := cachedDiskTemperature("sda", now); !ok || reading.Current != 35 {
+		t.Errorf("sda: cachedDiskTemperature() = %+v, %v; want 35, true", reading, ok)
+	}
+	// показание из кеша трёхдневной давности не выдаётся за текущее
+	if reading, ok := cachedDiskTemperature("sdb", now); ok {
+		t.Errorf("sdb: cachedDiskTemperature() = %+v, want stale reading rejected", reading)
+	}
+	if _, ok := cachedDiskTemperature("sdc", now); ok {
+		t.Error("sdc: cachedDiskTemperature() returned reading for disk without temperature")
+	}
+}
@@ -2,44 +2,124 @@ package metrics
 
 import "github.com/prometheus/client_golang/prometheus"
 
+// diskSchemaLabels — единая для Windows и Linux схема лейблов дисковых метрик:
+//
+//	device     — физическое устройство (/dev/sda, \\.\PHYSICALDRIVE0)
+//	mountpoint — точки монтирования диска через запятую (/,/home) или буква
+//	             диска Windows (C:); у disk_health_status — все буквы диска
+//	model      — модель накопителя
+//	serial     — серийный номер
+//	media_type — SSD/HDD/unknown
+var diskSchemaLabels = []string{"device", "mountpoint", "model", "serial", "media_type"}
+
+// legacyDiskLabels включает режим совместимости: помимо новой схемы метрики
+// получают прежние лейблы (disk, type), чтобы дашборды можно было мигрировать
+// постепенно.
+var legacyDiskLabels bool
+
 var (
-	DiskUsage = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "disk_usage_bytes",
-			Help: "Disk usage on system",
-		},
-		[]string{"disk", "model", "serial", "type"},
-	)
+	DiskUsage               *prometheus.GaugeVec
+	DiskUsagePercent        *prometheus.GaugeVec
+	DiskReadBytes           *prometheus.GaugeVec
+	DiskWriteBytes          *prometheus.GaugeVec
+	DiskHealthStatus        *prometheus.GaugeVec
+	DiskHealthCheckAge      *prometheus.GaugeVec
+	DiskTemperature         *prometheus.GaugeVec
+	DiskTemperatureWarning  *prometheus.GaugeVec
+	DiskTemperatureCritical *prometheus.GaugeVec
+)
 
-	DiskUsagePercent = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "disk_usage_percent",
-			Help: "Disk usage on system",
-		},
-		[]string{"disk", "model", "serial"},
-	)
+func init() {
+	initDiskMetrics()
+}
 
-	DiskReadBytes = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "disk_read_bytes_per_second",
-			Help: "Disk read bytes per second",
-		},
-		[]string{"disk", "model", "serial"},
-	)
+// ConfigureDiskLabels пересоздаёт дисковые метрики с нужным набором лейблов.
+// Должна вызываться до регистрации метрик.
+func ConfigureDiskLabels(legacy bool) {
+	legacyDiskLabels = legacy
+	initDiskMetrics()
+}
 
-	DiskWriteBytes = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "disk_write_bytes_per_second",
-			Help: "Disk write bytes per second",
-		},
-		[]string{"disk", "model", "serial"},
-	)
+func initDiskMetrics() {
+	DiskUsage = newDiskGaugeVec("disk_usage_bytes", "Disk usage on system (usage = total/free/used)",
+		[]string{"usage"}, []string{"disk", "type"})
+	DiskUsagePercent = newDiskGaugeVec("disk_usage_percent", "Disk usage on system",
+		nil, []string{"disk"})
+	DiskReadBytes = newDiskGaugeVec("disk_read_bytes_per_second", "Disk read bytes per second",
+		nil, []string{"disk"})
+	DiskWriteBytes = newDiskGaugeVec("disk_write_bytes_per_second", "Disk write bytes per second",
+		nil, []string{"disk"})
+	DiskHealthStatus = newDiskGaugeVec("disk_health_status", "Health status of disk (1 = healthy, 0 = warning/unhealthy/unknown)",
+		[]string{"status", "size"}, []string{"disk", "type"})
+	DiskHealthCheckAge = newDiskGaugeVec("disk_health_check_age_seconds", "Seconds since the last successful SMART/NVMe health check of disk",
+		nil, []string{"disk"})
+	DiskTemperature = newDiskGaugeVec("disk_temperature_celsius", "Current disk temperature in celsius",
+		nil, []string{"disk"})
+	DiskTemperatureWarning = newDiskGaugeVec("disk_temperature_warning_celsius", "Disk temperature warning threshold in celsius",
+		nil, []string{"disk"})
+	DiskTemperatureCritical = newDiskGaugeVec("disk_temperature_critical_celsius", "Disk temperature critical threshold in celsius",
+		nil, []string{"disk"})
+}
+
+func newDiskGaugeVec(name, help string, extra, legacy []string) *prometheus.GaugeVec {
+	labels := append([]string{}, diskSchemaLabels...)
+	labels = append(labels, extra...)
+	if legacyDiskLabels {
+		labels = append(labels, legacy...)
+	}
 
-	DiskHealthStatus = prometheus.NewGaugeVec(
+	return prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "disk_health_status",
-			Help: "Health status of disk",
+			Name: name,
+			Help: help,
 		},
-		[]string{"disk", "serial", "type", "status", "size"},
+		labels,
 	)
-)
+}
+
+// diskLabelSet — значения лейблов схемы для одного диска.
+type diskLabelSet struct {
+	Device     string
+	Mountpoint string
+	Model      string
+	Serial     string
+	MediaType  string
+}
+
+// labels собирает полный набор лейблов метрики: схема, дополнительные лейблы
+// метрики и, в режиме совместимости, прежние лейблы.
+func (d diskLabelSet) labels(extra, legacy prometheus.Labels) prometheus.Labels {
+	labels := prometheus.Labels{
+		"device":     orUnknown(d.Device),
+		"mountpoint": d.Mountpoint,
+		"model":      orUnknown(d.Model),
+		"serial":     orUnknown(d.Serial),
+		"media_type": orUnknown(d.MediaType),
+	}
+	for key, value := range extra {
+		labels[key] = value
+	}
+	if legacyDiskLabels {
+		for key, value := range legacy {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// diskHealthValue возвращает значение disk_health_status: 1 для исправного
+// диска, 0 для warning, unhealthy и unknown. Статусы и значения одинаковы на
+// Windows и Linux.
+func diskHealthValue(status string) float64 {
+	if status == "healthy" {
+		return 1
+	}
+	return 0
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -6,11 +6,10 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 	"unicode"
 
@@ -23,33 +22,24 @@ type diskMetadata struct {
 	Serial string
 }
 
-type diskHealthEntry struct {
-	Status    string
-	CheckedAt time.Time
-}
-
 type diskAggregate struct {
 	total uint64
 	used  uint64
 	free  uint64
 }
 
-type diskIOState struct {
-	stat      disk.IOCountersStat
-	timestamp time.Time
-}
-
-var (
-	diskHealthCache = map[string]diskHealthEntry{}
-	diskHealthMu    sync.Mutex
-)
-
 func RecordDiskUsage() {
 	go func() {
 		ticker := time.NewTicker(5 * time.Second)
 		defer ticker.Stop()
 
-		prevIO := make(map[string]diskIOState)
+		counters := newCounterTracker()
+		usageSeries := newGaugeSeries(DiskUsage)
+		percentSeries := newGaugeSeries(DiskUsagePercent)
+		readSeries := newGaugeSeries(DiskReadBytes)
+		writeSeries := newGaugeSeries(DiskWriteBytes)
+		healthSeries := newGaugeSeries(DiskHealthStatus)
+		ageSeries := newGaugeSeries(DiskHealthCheckAge)
 
 		for {
 			metadata := loadDiskMetadata()
@@ -109,112 +99,51 @@ func RecordDiskUsage() {
 				agg.total += usage.Total
 				agg.used += usage.Used
 				agg.free += usage.Free
-
 			}
 
+			// тот же список точек монтирования, что и у температурных метрик,
+			// чтобы наборы лейблов дисковых метрик совпадали
+			mountpoints := loadDiskMountpoints()
+
 			for baseName, agg := range aggregates {
-				meta := metadata[baseName]
-				model := meta.Model
-				if model == "" {
-					model = baseName
-				}
+				diskLabel := "/dev/" + baseName
+				labelSet := linuxDiskLabelSet(baseName, metadata[baseName], mountpoints[baseName])
+				legacy := prometheus.Labels{"disk": diskLabel}
 
-				serial := strings.TrimSpace(meta.Serial)
-				if serial == "" {
-					serial = "unknown"
-				}
+				usageSeries.Set(labelSet.labels(
+					prometheus.Labels{"usage": "total"},
+					prometheus.Labels{"disk": diskLabel, "type": "total"},
+				), float64(agg.total))
 
-				diskLabel := "/dev/" + baseName
+				usageSeries.Set(labelSet.labels(
+					prometheus.Labels{"usage": "free"},
+					prometheus.Labels{"disk": diskLabel, "type": "free"},
+				), float64(agg.free))
 
-				DiskUsage.With(prometheus.Labels{
-					"disk":   diskLabel,
-					"model":  model,
-					"serial": serial,
-					"type":   "total",
-				}).Set(float64(agg.total))
-
-				DiskUsage.With(prometheus.Labels{
-					"disk":   diskLabel,
-					"model":  model,
-					"serial": serial,
-					"type":   "free",
-				}).Set(float64(agg.free))
-
-				DiskUsage.With(prometheus.Labels{
-					"disk":   diskLabel,
-					"model":  model,
-					"serial": serial,
-					"type":   "used",
-				}).Set(float64(agg.used))
+				usageSeries.Set(labelSet.labels(
+					prometheus.Labels{"usage": "used"},
+					prometheus.Labels{"disk": diskLabel, "type": "used"},
+				), float64(agg.used))
 
 				usedPercent := 0.0
 				if agg.total > 0 {
 					usedPercent = (float64(agg.used) / float64(agg.total)) * 100
 				}
 
-				DiskUsagePercent.With(prometheus.Labels{
-					"disk":   diskLabel,
-					"model":  model,
-					"serial": serial,
-				}).Set(usedPercent)
+				percentSeries.Set(labelSet.labels(nil, legacy), usedPercent)
 
+				readRate, writeRate := 0.0, 0.0
 				if counter, ok := ioCounters[baseName]; ok {
-					state, exists := prevIO[baseName]
-					if exists {
-						elapsed := time.Since(state.timestamp).Seconds()
-						if elapsed <= 0 {
-							elapsed = 5
-						}
-
-						readRate := float64(counter.ReadBytes-state.stat.ReadBytes) / elapsed
-						writeRate := float64(counter.WriteBytes-state.stat.WriteBytes) / elapsed
-
-						if readRate < 0 {
-							readRate = 0
-						}
-						if writeRate < 0 {
-							writeRate = 0
-						}
-
-						DiskReadBytes.With(prometheus.Labels{
-							"disk":   diskLabel,
-							"model":  model,
-							"serial": serial,
-						}).Set(readRate)
-
-						DiskWriteBytes.With(prometheus.Labels{
-							"disk":   diskLabel,
-							"model":  model,
-							"serial": serial,
-						}).Set(writeRate)
-					} else {
-						DiskReadBytes.With(prometheus.Labels{
-							"disk":   diskLabel,
-							"model":  model,
-							"serial": serial,
-						}).Set(0)
-
-						DiskWriteBytes.With(prometheus.Labels{
-							"disk":   diskLabel,
-							"model":  model,
-							"serial": serial,
-						}).Set(0)
-					}
-
-					prevIO[baseName] = diskIOState{stat: counter, timestamp: time.Now()}
-				} else {
-					DiskReadBytes.With(prometheus.Labels{
-						"disk":   diskLabel,
-						"model":  model,
-						"serial": serial,
-					}).Set(0)
-					DiskWriteBytes.With(prometheus.Labels{
-						"disk":   diskLabel,
-						"model":  model,
-						"serial": serial,
-					}).Set(0)
+					now := time.Now()
+					// major:minor меняется, если устройство пересоздано под тем же именем
+					identity := readSysfsValue(filepath.Join("/sys/block", baseName, "dev"))
+					readRate = counters.Observe(baseName+"|read", identity, counter.ReadBytes, counter64, now).rate()
+					writeRate = counters.Observe(baseName+"|write", identity, counter.WriteBytes, counter64, now).rate()
 				}
 
+				readSeries.Set(labelSet.labels(nil, legacy), readRate)
+				writeSeries.Set(labelSet.labels(nil, legacy), writeRate)
+
 				sizeBytes := agg.total
 				if sizeBytes == 0 {
 					sectors := readSysfsValue(filepath.Join("/sys/block", baseName, "size"))
@@ -225,25 +154,79 @@ func RecordDiskUsage() {
 					}
 				}
 
-				healthDisk := model
-				if healthDisk == "" {
-					healthDisk = diskLabel
-				}
+				// серию с прежним статусом удаляет Flush
+				status := diskHealthStatus(baseName)
+				healthSeries.Set(labelSet.labels(
+					prometheus.Labels{
+						"status": status,
+						"size":   fmt.Sprintf("%d", sizeBytes),
+					},
+					prometheus.Labels{"disk": labelSet.Model, "type": labelSet.MediaType},
+				), diskHealthValue(status))
+
+				recordDiskHealthCheckAge(ageSeries, baseName, labelSet)
+			}
 
-				DiskHealthStatus.With(prometheus.Labels{
-					"disk":   healthDisk,
-					"serial": serial,
-					"type":   diskPhysicalType(baseName),
-					"status": diskHealthStatus(baseName),
-					"size":   fmt.Sprintf("%d", sizeBytes),
-				}).Set(1)
+			// серии отмонтированных и отключённых дисков и прежних наборов
+			// точек монтирования удаляются
+			for _, series := range []*gaugeSeries{usageSeries, percentSeries, readSeries, writeSeries, healthSeries, ageSeries} {
+				series.Flush()
 			}
 
+			counters.Prune(time.Now().Add(-time.Minute))
 			<-ticker.C
 		}
 	}()
 }
 
+// linuxDiskLabelSet заполняет единую схему лейблов для физического диска.
+func linuxDiskLabelSet(baseName string, meta diskMetadata, mountpoints []string) diskLabelSet {
+	model := meta.Model
+	if model == "" {
+		model = baseName
+	}
+
+	sorted := append([]string{}, mountpoints...)
+	sort.Strings(sorted)
+
+	return diskLabelSet{
+		Device:     "/dev/" + baseName,
+		Mountpoint: strings.Join(sorted, ","),
+		Model:      model,
+		Serial:     strings.TrimSpace(meta.Serial),
+		MediaType:  diskPhysicalType(baseName),
+	}
+}
+
+// loadDiskMountpoints возвращает точки монтирования физических дисков.
+func loadDiskMountpoints() map[string][]string {
+	mountpoints := make(map[string][]string)
+
+	partitions, err := disk.Partitions(true)
+	if err != nil {
+		return mountpoints
+	}
+
+	seenDevices := make(map[string]struct{})
+	for _, part := range partitions {
+		if part.Mountpoint == "" {
+			continue
+		}
+		if _, skip := seenDevices[part.Device]; skip {
+			continue
+		}
+		seenDevices[part.Device] = struct{}{}
+
+		baseName := diskBaseName(part.Device)
+		if baseName == "" {
+			continue
+		}
+		mountpoints[baseName] = append(mountpoints[baseName], part.Mountpoint)
+	}
+
+	return mountpoints
+}
+
 func loadDiskMetadata() map[string]diskMetadata {
 	entries, err := os.ReadDir("/sys/block")
 	if err != nil {
@@ -324,88 +307,3 @@ func diskPhysicalType(base string) string {
 
 	return "unknown"
 }
-
-func diskHealthStatus(base string) string {
-	diskHealthMu.Lock()
-	entry, ok := diskHealthCache[base]
-	diskHealthMu.Unlock()
-
-	if ok && time.Since(entry.CheckedAt) < time.Minute {
-		return entry.Status
-	}
-
-	status := queryDiskHealth(base)
-	if status == "" {
-		status = "unknown"
-	}
-
-	diskHealthMu.Lock()
-	diskHealthCache[base] = diskHealthEntry{Status: status, CheckedAt: time.Now()}
-	diskHealthMu.Unlock()
-
-	return status
-}
-
-func queryDiskHealth(base string) string {
-	if strings.HasPrefix(base, "nvme") {
-		controller := base
-		if idx := strings.Index(base, "n"); idx > 0 {
-			controller = base[:idx]
-		}
-
-		if status := runNvmeCLI("/dev/" + controller); status != "" {
-			return status
-		}
-
-		if status := runSmartctl([]string{"-H", "-d", "nvme", "/dev/" + controller}); status != "" {
-			return status
-		}
-	}
-
-	return runSmartctl([]string{"-H", "/dev/" + base})
-}
-
-func runSmartctl(args []string) string {
-	cmd := exec.Command("smartctl", args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return ""
-	}
-
-	lower := strings.ToLower(string(output))
-	switch {
-	case strings.Contains(lower, "passed"):
-		return "healthy"
-	case strings.Contains(lower, "warning"), strings.Contains(lower, "prefail"), strings.Contains(lower, "degrad"):
-		return "warning"
-	case strings.Contains(lower, "fail"):
-		return "unhealthy"
-	default:
-		return "unknown"
-	}
-}
-
-func runNvmeCLI(device string) string {
-	cmd := exec.Command("nvme", "smart-log", device)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return ""
-	}
-
-	for _, line := range strings.Split(string(output), "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "critical_warning") {
-			fields := strings.Fields(line)
-			if len(fields) >= 2 {
-				value := fields[len(fields)-1]
-				value = strings.TrimPrefix(value, "0x")
-				if value == "0" {
-					return "healthy"
-				}
-				return "warning"
-			}
-		}
-	}
-
-	return ""
-}
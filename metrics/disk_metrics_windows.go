@@ -5,6 +5,9 @@ package metrics
 import (
 	"fmt"
 	"log"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/StackExchange/wmi"
@@ -13,6 +16,7 @@ import (
 )
 
 type MSFT_PhysicalDisk struct {
+	DeviceId     string
 	FriendlyName string
 	SerialNumber string
 	MediaType    uint16
@@ -27,6 +31,16 @@ type Win32_LogicalDisk struct {
 	FileSystem string
 }
 
+type Win32_LogicalDiskToPartition struct {
+	Antecedent string
+	Dependent  string
+}
+
+var (
+	wmiDiskIndexRe = regexp.MustCompile(`Disk #(\d+)`)
+	wmiDeviceIDRe  = regexp.MustCompile(`DeviceID="([^"]+)"`)
+)
+
 // GetPhysicalDisks retrieves information about physical disks in the system
 // by querying the MSFT_PhysicalDisk WMI class. It returns a slice of
 // MSFT_PhysicalDisk structs containing details such as friendly name,
@@ -35,7 +49,7 @@ type Win32_LogicalDisk struct {
 func GetPhysicalDisks() ([]MSFT_PhysicalDisk, error) {
 	var physicalDisks []MSFT_PhysicalDisk
 	err := wmi.QueryNamespace(
-		"SELECT FriendlyName, SerialNumber, MediaType, HealthStatus, Size FROM MSFT_PhysicalDisk",
+		"SELECT DeviceId, FriendlyName, SerialNumber, MediaType, HealthStatus, Size FROM MSFT_PhysicalDisk",
 		&physicalDisks,
 		"ROOT\\Microsoft\\Windows\\Storage",
 	)
@@ -62,6 +76,44 @@ func GetLogicalDisks() ([]Win32_LogicalDisk, error) {
 	return logicalDisks, nil
 }
 
+// GetLogicalDiskPhysicalIndexes maps logical drive letters (e.g. "C:") to the
+// index of the physical disk they reside on by querying the
+// Win32_LogicalDiskToPartition association class. The index matches the
+// DeviceId property of MSFT_PhysicalDisk and the N in \\.\PHYSICALDRIVEN.
+func GetLogicalDiskPhysicalIndexes() (map[string]string, error) {
+	var links []Win32_LogicalDiskToPartition
+	if err := wmi.Query("SELECT Antecedent, Dependent FROM Win32_LogicalDiskToPartition", &links); err != nil {
+		return nil, fmt.Errorf("error getting logical disk to partition mapping: %v", err)
+	}
+
+	indexes := make(map[string]string, len(links))
+	for _, link := range links {
+		disk := wmiDiskIndexRe.FindStringSubmatch(link.Antecedent)
+		drive := wmiDeviceIDRe.FindStringSubmatch(link.Dependent)
+		if len(disk) < 2 || len(drive) < 2 {
+			continue
+		}
+		indexes[drive[1]] = disk[1]
+	}
+	return indexes, nil
+}
+
+// windowsDiskLabelSet fills the shared disk label schema for a physical disk.
+// Mountpoints are the drive letters located on the disk: a single letter for
+// usage and IO series, all letters of the disk for health.
+func windowsDiskLabelSet(drive MSFT_PhysicalDisk, mountpoints []string) diskLabelSet {
+	sorted := append([]string{}, mountpoints...)
+	sort.Strings(sorted)
+
+	return diskLabelSet{
+		Device:     `\\.\PHYSICALDRIVE` + drive.DeviceId,
+		Mountpoint: strings.Join(sorted, ","),
+		Model:      drive.FriendlyName,
+		Serial:     strings.TrimSpace(drive.SerialNumber),
+		MediaType:  mediaTypeToString(drive.MediaType),
+	}
+}
+
 // GetDiskIOCounters retrieves the disk I/O counters for a specific device
 // identified by deviceID. It returns a disk.IOCountersStat struct containing
 // the I/O statistics for the device, or an error if the statistics cannot
@@ -85,7 +137,7 @@ func GetDiskIOCounters(deviceID string) (disk.IOCountersStat, error) {
 //	3: Hard Disk Drive (HDD)
 //	4: Solid-State Drive (SSD)
 //
-// Any other value is returned as "Unknown".
+// Any other value is returned as "unknown".
 func mediaTypeToString(mediaType uint16) string {
 	switch mediaType {
 	case 3:
@@ -93,7 +145,7 @@ func mediaTypeToString(mediaType uint16) string {
 	case 4:
 		return "SSD"
 	default:
-		return "Unkown"
+		return "unknown"
 	}
 }
 
@@ -101,42 +153,49 @@ func mediaTypeToString(mediaType uint16) string {
 // string corresponding to the health status of the disk. The mapping is as
 // follows:
 //
-//	0: Healthy
-//	1: Warning
-//	2: Unhealthy
+//	0: healthy
+//	1: warning
+//	2: unhealthy
 //
-// Any other value is returned as "Unknown".
+// Any other value is returned as "unknown". The values match the statuses
+// reported on Linux.
 func healthStatusToString(status uint16) string {
 	switch status {
 	case 0:
-		return "Healthy"
+		return "healthy"
 	case 1:
-		return "Warning"
+		return "warning"
 	case 2:
-		return "Unhealthy"
+		return "unhealthy"
 	default:
-		return "Unknown"
+		return "unknown"
 	}
 }
 
 // RecordDiskUsage starts a goroutine which records disk usage metrics on a
-// regular schedule. It queries the MSFT_PhysicalDisk WMI class to get a list
-// of physical disks and their media types, and then queries the diskutil
-// library to get the current disk usage and IO counters for each physical
-// disk. It then records the following metrics:
+// regular schedule. Usage and IO are reported per drive letter: the letter is
+// the mountpoint label, and the device, model and serial labels identify the
+// physical disk the letter resides on (mapped through
+// Win32_LogicalDiskToPartition). Health is reported per physical disk. It
+// records the following metrics:
 //
-// * disk_usage_bytes: The total, used, and free space on each disk
-// * disk_usage_percent: The percentage of used space on each disk
-// * disk_read_bytes_per_second: The read speed of each disk
-// * disk_write_bytes_per_second: The write speed of each disk
+// * disk_usage_bytes: The total, used, and free space on each drive letter
+// * disk_usage_percent: The percentage of used space on each drive letter
+// * disk_read_bytes_per_second: The read speed of each drive letter
+// * disk_write_bytes_per_second: The write speed of each drive letter
 // * disk_health_status: The health status of each physical disk
 //
-// The metrics are recorded in a goroutine which runs every 5 seconds.
+// Series of drive letters and disks that disappear are deleted. The metrics
+// are recorded in a goroutine which runs every 5 seconds.
 func RecordDiskUsage() {
 	go func() {
 
-		var prevIO = make(map[string]disk.IOCountersStat)
-		modelMap := make(map[string]string)
+		counters := newCounterTracker()
+		usageSeries := newGaugeSeries(DiskUsage)
+		percentSeries := newGaugeSeries(DiskUsagePercent)
+		readSeries := newGaugeSeries(DiskReadBytes)
+		writeSeries := newGaugeSeries(DiskWriteBytes)
+		healthSeries := newGaugeSeries(DiskHealthStatus)
 
 		// Получаем информацию о физических дисках один раз при старте
 		physicalDisks, err := GetPhysicalDisks()
@@ -145,30 +204,16 @@ func RecordDiskUsage() {
 			return
 		}
 
-		// Создаем маппинг имен дисков к их описаниям
+		physicalByIndex := make(map[string]MSFT_PhysicalDisk, len(physicalDisks))
 		for _, drive := range physicalDisks {
-			mediaTypeStr := mediaTypeToString(drive.MediaType)
-			healthStatusStr := healthStatusToString(drive.HealthStatus)
+			physicalByIndex[drive.DeviceId] = drive
 			log.Printf("Detected physical disk: FriendlyName=%s, SerialNumber=%s, MediaType=%s, Size=%d",
-				drive.FriendlyName, drive.SerialNumber, mediaTypeStr, drive.Size)
-
-			modelMap[drive.FriendlyName] = fmt.Sprintf("%s (SN: %s, Type: %s, Health: %s, Size: %d)",
-				drive.FriendlyName, drive.SerialNumber, mediaTypeStr, healthStatusStr, drive.Size)
-
-			// Записываем метрику здоровья диска
-			healthValue := 1.0
-			if healthStatusStr != "Healthy" {
-				healthValue = 0.0
-			}
-			DiskHealthStatus.With(prometheus.Labels{
-				"disk":   drive.FriendlyName,
-				"serial": drive.SerialNumber,
-				"type":   mediaTypeStr,
-				"status": healthStatusStr,
-				"size":   fmt.Sprintf("%d", drive.Size),
-			}).Set(healthValue)
+				drive.FriendlyName, drive.SerialNumber, mediaTypeToString(drive.MediaType), drive.Size)
 		}
 
+		// буквы, не сопоставленные физическому диску, логируются один раз
+		unmapped := make(map[string]bool)
+
 		for {
 			// Получаем информацию о логических дисках
 			partitions, err := GetLogicalDisks()
@@ -178,37 +223,53 @@ func RecordDiskUsage() {
 				continue
 			}
 
+			// Сопоставляем буквы дисков с физическими дисками
+			driveIndexes, err := GetLogicalDiskPhysicalIndexes()
+			if err != nil {
+				log.Printf("%v", err)
+				driveIndexes = map[string]string{}
+			}
+
+			letters := make(map[string][]string)
+			now := time.Now()
+
 			for _, part := range partitions {
-				model := modelMap[part.DeviceID]
+				index := driveIndexes[part.DeviceID]
+				drive, ok := physicalByIndex[index]
+				if !ok {
+					if !unmapped[part.DeviceID] {
+						log.Printf("drive %s is not mapped to a physical disk, skipped", part.DeviceID)
+						unmapped[part.DeviceID] = true
+					}
+					continue
+				}
+				letters[drive.DeviceId] = append(letters[drive.DeviceId], part.DeviceID)
+
+				labelSet := windowsDiskLabelSet(drive, []string{part.DeviceID})
+				// прежний лейбл disk содержал букву диска
+				legacy := prometheus.Labels{"disk": part.DeviceID}
 
 				// Записываем метрики использования диска
-				DiskUsage.With(prometheus.Labels{
-					"disk":   part.DeviceID,
-					"model":  model,
-					"serial": "unknown",
-					"type":   "total",
-				}).Set(float64(part.Size))
-
-				DiskUsage.With(prometheus.Labels{
-					"disk":   part.DeviceID,
-					"model":  model,
-					"serial": "unknown",
-					"type":   "free",
-				}).Set(float64(part.FreeSpace))
-
-				DiskUsage.With(prometheus.Labels{
-					"disk":   part.DeviceID,
-					"model":  model,
-					"serial": "unknown",
-					"type":   "used",
-				}).Set(float64(part.Size - part.FreeSpace))
-
-				usedPercent := (float64(part.Size-part.FreeSpace) / float64(part.Size)) * 100
-				DiskUsagePercent.With(prometheus.Labels{
-					"disk":   part.DeviceID,
-					"model":  model,
-					"serial": "unknown",
-				}).Set(usedPercent)
+				usageSeries.Set(labelSet.labels(
+					prometheus.Labels{"usage": "total"},
+					prometheus.Labels{"disk": part.DeviceID, "type": "total"},
+				), float64(part.Size))
+
+				usageSeries.Set(labelSet.labels(
+					prometheus.Labels{"usage": "free"},
+					prometheus.Labels{"disk": part.DeviceID, "type": "free"},
+				), float64(part.FreeSpace))
+
+				usageSeries.Set(labelSet.labels(
+					prometheus.Labels{"usage": "used"},
+					prometheus.Labels{"disk": part.DeviceID, "type": "used"},
+				), float64(part.Size-part.FreeSpace))
+
+				usedPercent := 0.0
+				if part.Size > 0 {
+					usedPercent = (float64(part.Size-part.FreeSpace) / float64(part.Size)) * 100
+				}
+				percentSeries.Set(labelSet.labels(nil, legacy), usedPercent)
 
 				// Получаем и записываем метрики IO
 				current, err := GetDiskIOCounters(part.DeviceID)
@@ -217,25 +278,33 @@ func RecordDiskUsage() {
 					continue
 				}
 
-				if prev, ok := prevIO[part.DeviceID]; ok {
-					duration := 5.0 // Период обновления в секундах
-					readSpeed := float64(current.ReadBytes-prev.ReadBytes) / duration
-					writeSpeed := float64(current.WriteBytes-prev.WriteBytes) / duration
-
-					DiskReadBytes.With(prometheus.Labels{
-						"disk":   part.DeviceID,
-						"model":  model,
-						"serial": "unknown",
-					}).Set(readSpeed)
-
-					DiskWriteBytes.With(prometheus.Labels{
-						"disk":   part.DeviceID,
-						"model":  model,
-						"serial": "unknown",
-					}).Set(writeSpeed)
-				}
-				prevIO[part.DeviceID] = current
+				// как и на Linux, первый цикл даёт нулевую скорость
+				identity := labelSet.Device
+				readSeries.Set(labelSet.labels(nil, legacy),
+					counters.Observe(part.DeviceID+"|read", identity, current.ReadBytes, counter64, now).rate())
+				writeSeries.Set(labelSet.labels(nil, legacy),
+					counters.Observe(part.DeviceID+"|write", identity, current.WriteBytes, counter64, now).rate())
 			}
+
+			// Метрика здоровья публикуется для всех физических дисков, в том
+			// числе без букв
+			for _, drive := range physicalDisks {
+				status := healthStatusToString(drive.HealthStatus)
+				labelSet := windowsDiskLabelSet(drive, letters[drive.DeviceId])
+				healthSeries.Set(labelSet.labels(
+					prometheus.Labels{
+						"status": status,
+						"size":   fmt.Sprintf("%d", drive.Size),
+					},
+					prometheus.Labels{"disk": drive.FriendlyName, "type": labelSet.MediaType},
+				), diskHealthValue(status))
+			}
+
+			for _, series := range []*gaugeSeries{usageSeries, percentSeries, readSeries, writeSeries, healthSeries} {
+				series.Flush()
+			}
+
+			counters.Prune(time.Now().Add(-time.Minute))
 			time.Sleep(5 * time.Second)
 		}
 
@@ -0,0 +1,179 @@
+//go:build linux
+
+package metrics
+
+import (
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type diskTemperatureReading struct {
+	Current     float64
+	Warning     float64
+	Critical    float64
+	HasCurrent  bool
+	HasWarning  bool
+	HasCritical bool
+	CheckedAt   time.Time
+}
+
+type smartctlTemperatureReport struct {
+	Temperature struct {
+		Current    *float64 `json:"current"`
+		OpLimitMax *float64 `json:"op_limit_max"`
+		LimitMax   *float64 `json:"limit_max"`
+	} `json:"temperature"`
+	NvmeThreshold struct {
+		Warning  *float64 `json:"warning"`
+		Critical *float64 `json:"critical"`
+	} `json:"nvme_composite_temperature_threshold"`
+}
+
+func RecordDiskTemperature() {
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+
+		temperatureSeries := newGaugeSeries(DiskTemperature)
+		warningSeries := newGaugeSeries(DiskTemperatureWarning)
+		criticalSeries := newGaugeSeries(DiskTemperatureCritical)
+
+		for {
+			metadata := loadDiskMetadata()
+			mountpoints := loadDiskMountpoints()
+
+			for baseName, meta := range metadata {
+				reading, ok := readDiskTemperatureHwmon(baseName)
+				if !ok {
+					// SMART опрашивается фоновым воркером здоровья дисков
+					reading, _ = cachedDiskTemperature(baseName, time.Now())
+				}
+
+				if !reading.HasCurrent {
+					continue
+				}
+
+				labelSet := linuxDiskLabelSet(baseName, meta, mountpoints[baseName])
+				labels := labelSet.labels(nil, prometheus.Labels{"disk": labelSet.Device})
+				temperatureSeries.Set(labels, reading.Current)
+				if reading.HasWarning {
+					warningSeries.Set(labels, reading.Warning)
+				}
+				if reading.HasCritical {
+					criticalSeries.Set(labels, reading.Critical)
+				}
+			}
+
+			// серии обновляются на месте, удаляются только исчезнувшие, чтобы
+			// scrape во время опроса не видел пустых векторов
+			temperatureSeries.Flush()
+			warningSeries.Flush()
+			criticalSeries.Flush()
+
+			<-ticker.C
+		}
+	}()
+}
+
+// readDiskTemperatureHwmon читает температуру из hwmon-устройства диска:
+// drivetemp для SATA (device/hwmon/hwmonN) и nvme (контроллер/hwmonN).
+func readDiskTemperatureHwmon(baseName string) (diskTemperatureReading, bool) {
+	deviceDir := filepath.Join("/sys/block", baseName, "device")
+
+	var candidates []string
+	for _, pattern := range []string{
+		filepath.Join(deviceDir, "hwmon", "hwmon*"),
+		filepath.Join(deviceDir, "hwmon*"),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		candidates = append(candidates, matches...)
+	}
+
+	for _, dir := range candidates {
+		reading := diskTemperatureReading{}
+		reading.Current, reading.HasCurrent = readMilliCelsius(filepath.Join(dir, "temp1_input"))
+		if !reading.HasCurrent {
+			continue
+		}
+		reading.Warning, reading.HasWarning = readMilliCelsius(filepath.Join(dir, "temp1_max"))
+		reading.Critical, reading.HasCritical = readMilliCelsius(filepath.Join(dir, "temp1_crit"))
+		return reading, true
+	}
+
+	return diskTemperatureReading{}, false
+}
+
+func readMilliCelsius(path string) (float64, bool) {
+	raw := readSysfsValue(path)
+	if raw == "" {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value / 1000, true
+}
+
+func readDiskTemperatureSmart(baseName string) diskTemperatureReading {
+	args := []string{"-a", "-j", "/dev/" + baseName}
+	if strings.HasPrefix(baseName, "nvme") {
+		args = []string{"-a", "-j", "-d", "nvme", "/dev/" + nvmeControllerName(baseName)}
+	}
+
+	// smartctl возвращает битовую маску в коде выхода даже при корректном выводе
+	output, _ := exec.Command("smartctl", args...).Output()
+	if len(output) == 0 {
+		return diskTemperatureReading{}
+	}
+
+	return parseSmartctlTemperature(output)
+}
+
+func parseSmartctlTemperature(data []byte) diskTemperatureReading {
+	var report smartctlTemperatureReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return diskTemperatureReading{}
+	}
+
+	reading := diskTemperatureReading{}
+	if report.Temperature.Current != nil {
+		reading.Current, reading.HasCurrent = *report.Temperature.Current, true
+	}
+
+	switch {
+	case report.NvmeThreshold.Warning != nil:
+		reading.Warning, reading.HasWarning = *report.NvmeThreshold.Warning, true
+	case report.Temperature.OpLimitMax != nil:
+		reading.Warning, reading.HasWarning = *report.Temperature.OpLimitMax, true
+	}
+
+	switch {
+	case report.NvmeThreshold.Critical != nil:
+		reading.Critical, reading.HasCritical = *report.NvmeThreshold.Critical, true
+	case report.Temperature.LimitMax != nil:
+		reading.Critical, reading.HasCritical = *report.Temperature.LimitMax, true
+	}
+
+	return reading
+}
+
+// nvmeControllerName возвращает имя контроллера для namespace: nvme0n1 -> nvme0.
+func nvmeControllerName(base string) string {
+	if idx := strings.LastIndex(base, "n"); idx > len("nvme") {
+		controller := base[:idx]
+		// nvme0c0n1 -> nvme0
+		if cIdx := strings.Index(controller[len("nvme"):], "c"); cIdx >= 0 {
+			controller = controller[:len("nvme")+cIdx]
+		}
+		return controller
+	}
+	return base
+}
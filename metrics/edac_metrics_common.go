@@ -0,0 +1,22 @@
+package metrics
+
+// Счётчики ведёт драйвер EDAC, агент публикует их абсолютные значения.
+var (
+	EdacErrors = newConstCounterVec(
+		"edac_errors_total",
+		"ECC memory errors reported by the EDAC memory controller (type = correctable/uncorrectable)",
+		[]string{"controller", "type"},
+	)
+
+	EdacDimmErrors = newConstCounterVec(
+		"edac_dimm_errors_total",
+		"ECC memory errors per DIMM; locator matches memory_module_info when the EDAC label can be correlated",
+		[]string{"controller", "dimm", "label", "locator", "type"},
+	)
+
+	EdacCsrowErrors = newConstCounterVec(
+		"edac_csrow_errors_total",
+		"ECC memory errors per chip-select row for drivers without per-DIMM accounting",
+		[]string{"controller", "csrow", "type"},
+	)
+)
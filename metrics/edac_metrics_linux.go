@@ -0,0 +1,218 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const edacSysfsRoot = "/sys/devices/system/edac/mc"
+
+// edacCounts — счётчики исправленных (ce) и неисправленных (ue) ошибок. Каждый
+// из файлов может отсутствовать независимо от другого.
+type edacCounts struct {
+	Correctable      uint64
+	Uncorrectable    uint64
+	HasCorrectable   bool
+	HasUncorrectable bool
+}
+
+type edacDimm struct {
+	Name  string
+	Label string
+	edacCounts
+}
+
+type edacCsrow struct {
+	Name string
+	edacCounts
+}
+
+type edacController struct {
+	Name   string
+	Dimms  []edacDimm
+	Csrows []edacCsrow
+	edacCounts
+}
+
+// RecordEdacMetrics публикует ce_count/ue_count из sysfs как абсолютные
+// значения счётчиков: ошибки, накопленные до запуска агента, тоже видны.
+func RecordEdacMetrics() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			recordEdacControllers(readEdacControllers(edacSysfsRoot))
+			<-ticker.C
+		}
+	}()
+}
+
+func recordEdacControllers(controllers []edacController) {
+	errors := EdacErrors.Batch()
+	dimmErrors := EdacDimmErrors.Batch()
+	csrowErrors := EdacCsrowErrors.Batch()
+
+	for _, ctrl := range controllers {
+		setEdacCounts(errors, prometheus.Labels{"controller": ctrl.Name}, ctrl.edacCounts)
+
+		for _, dimm := range ctrl.Dimms {
+			setEdacCounts(dimmErrors, prometheus.Labels{
+				"controller": ctrl.Name,
+				"dimm":       dimm.Name,
+				"label":      orUnknown(dimm.Label),
+				"locator":    orUnknown(edacDimmLocator(dimm.Label)),
+			}, dimm.edacCounts)
+		}
+
+		// csrow публикуются только если драйвер не ведёт учёт по DIMM
+		if len(ctrl.Dimms) == 0 {
+			for _, csrow := range ctrl.Csrows {
+				setEdacCounts(csrowErrors, prometheus.Labels{"controller": ctrl.Name, "csrow": csrow.Name}, csrow.edacCounts)
+			}
+		}
+	}
+
+	// серии выгруженных контроллеров и прежних меток удаляются
+	errors.Commit()
+	dimmErrors.Commit()
+	csrowErrors.Commit()
+}
+
+// setEdacCounts добавляет в batch серии type=correctable/uncorrectable с
+// лейблами labels. Непрочитанный счётчик не публикуется, а не заменяется нулём.
+func setEdacCounts(batch *constCounterBatch, labels prometheus.Labels, counts edacCounts) {
+	set := func(typ string, value uint64) {
+		series := prometheus.Labels{"type": typ}
+		for name, label := range labels {
+			series[name] = label
+		}
+		batch.Set(series, float64(value))
+	}
+
+	if counts.HasCorrectable {
+		set("correctable", counts.Correctable)
+	}
+	if counts.HasUncorrectable {
+		set("uncorrectable", counts.Uncorrectable)
+	}
+}
+
+// readEdacControllers читает mcN из root (/sys/devices/system/edac/mc). Новые
+// ядра описывают модули в каталогах dimmN (или rankN), старые — только csrowN.
+func readEdacControllers(root string) []edacController {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var controllers []edacController
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "mc") {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+
+		ctrl := edacController{
+			Name:       entry.Name(),
+			edacCounts: readEdacCounts(filepath.Join(dir, "ce_count"), filepath.Join(dir, "ue_count")),
+		}
+
+		children, err := os.ReadDir(dir)
+		if err != nil {
+			controllers = append(controllers, ctrl)
+			continue
+		}
+
+		for _, child := range children {
+			name := child.Name()
+			childDir := filepath.Join(dir, name)
+
+			switch {
+			case strings.HasPrefix(name, "dimm"), strings.HasPrefix(name, "rank"):
+				ctrl.Dimms = append(ctrl.Dimms, edacDimm{
+					Name:  name,
+					Label: readSysfsValue(filepath.Join(childDir, "dimm_label")),
+					edacCounts: readEdacCounts(
+						filepath.Join(childDir, "dimm_ce_count"),
+						filepath.Join(childDir, "dimm_ue_count"),
+					),
+				})
+			case strings.HasPrefix(name, "csrow"):
+				ctrl.Csrows = append(ctrl.Csrows, edacCsrow{
+					Name:       name,
+					edacCounts: readEdacCounts(filepath.Join(childDir, "ce_count"), filepath.Join(childDir, "ue_count")),
+				})
+			}
+		}
+
+		controllers = append(controllers, ctrl)
+	}
+
+	return controllers
+}
+
+func readEdacCounts(cePath, uePath string) edacCounts {
+	ce, ceErr := strconv.ParseUint(readSysfsValue(cePath), 10, 64)
+	ue, ueErr := strconv.ParseUint(readSysfsValue(uePath), 10, 64)
+	return edacCounts{
+		Correctable:      ce,
+		Uncorrectable:    ue,
+		HasCorrectable:   ceErr == nil,
+		HasUncorrectable: ueErr == nil,
+	}
+}
+
+// edacDimmLocator сопоставляет метку DIMM из EDAC (обычно шелкография платы,
+// например "DIMM_A1" или "CPU0_DIMM_A1") с Locator модуля из SMBIOS. Сравнение
+// ведётся без учёта регистра и разделителей; при отсутствии совпадения
+// возвращается пустая строка.
+func edacDimmLocator(label string) string {
+	normalizedLabel := normalizeLocator(label)
+	if normalizedLabel == "" {
+		return ""
+	}
+
+	memoryInventoryMu.RLock()
+	defer memoryInventoryMu.RUnlock()
+
+	var suffixMatch string
+	suffixMatches := 0
+	for _, module := range memoryInventory {
+		locator := normalizeLocator(module.Locator)
+		switch {
+		case locator == "":
+			continue
+		case locator == normalizedLabel:
+			return module.Locator
+		case strings.HasSuffix(normalizedLabel, locator):
+			suffixMatch = module.Locator
+			suffixMatches++
+		}
+	}
+
+	// совпадение по суффиксу принимается, только если оно однозначно: на
+	// многосокетных платах слоты DIMM_A1 есть у каждого процессора
+	if suffixMatches == 1 {
+		return suffixMatch
+	}
+	return ""
+}
+
+func normalizeLocator(value string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,153 @@
+//go:build linux
+
+package metrics
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testdata/edac/dimm — /sys/devices/system/edac/mc двухсокетного сервера с
+// учётом ошибок по DIMM (skx_edac), testdata/edac/csrow — старый драйвер,
+// который ведёт только csrowN. В обоих некоторые файлы ue_count отсутствуют.
+
+func TestReadEdacControllers(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   []edacController
+	}{
+		{"dimm", []edacController{
+			{
+				Name: "mc0",
+				Dimms: []edacDimm{
+					{Name: "dimm0", Label: "CPU0_DIMM_A1", edacCounts: edacCounts{Correctable: 5, HasCorrectable: true, HasUncorrectable: true}},
+					{Name: "dimm1", Label: "CPU0_DIMM_B1", edacCounts: edacCounts{HasCorrectable: true}},
+				},
+				Csrows:     []edacCsrow{{Name: "csrow0", edacCounts: edacCounts{Correctable: 5, HasCorrectable: true, HasUncorrectable: true}}},
+				edacCounts: edacCounts{Correctable: 5, HasCorrectable: true, HasUncorrectable: true},
+			},
+			{
+				Name: "mc1",
+				Dimms: []edacDimm{
+					{Name: "dimm0", Label: "CPU1_DIMM_A1", edacCounts: edacCounts{Uncorrectable: 1, HasCorrectable: true, HasUncorrectable: true}},
+				},
+				edacCounts: edacCounts{Uncorrectable: 1, HasCorrectable: true, HasUncorrectable: true},
+			},
+		}},
+		{"csrow", []edacController{
+			{
+				Name: "mc0",
+				Csrows: []edacCsrow{
+					{Name: "csrow0", edacCounts: edacCounts{Correctable: 12, HasCorrectable: true, HasUncorrectable: true}},
+					{Name: "csrow1", edacCounts: edacCounts{HasCorrectable: true, HasUncorrectable: true}},
+				},
+				edacCounts: edacCounts{Correctable: 12, HasCorrectable: true, HasUncorrectable: true},
+			},
+			{
+				Name:       "mc1",
+				Csrows:     []edacCsrow{{Name: "csrow0", edacCounts: edacCounts{Correctable: 2, HasCorrectable: true}}},
+				edacCounts: edacCounts{Correctable: 2, HasCorrectable: true},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		got := readEdacControllers(filepath.Join("testdata", "edac", tt.layout, "mc"))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: readEdacControllers() = %+v, want %+v", tt.layout, got, tt.want)
+		}
+	}
+
+	if got := readEdacControllers(filepath.Join("testdata", "edac", "missing")); got != nil {
+		t.Errorf("readEdacControllers(missing) = %+v, want nil", got)
+	}
+}
+
+func setMemoryInventory(t *testing.T, locators ...string) {
+	t.Helper()
+
+	modules := make([]memoryModule, 0, len(locators))
+	for _, locator := range locators {
+		modules = append(modules, memoryModule{Locator: locator})
+	}
+
+	memoryInventoryMu.Lock()
+	previous := memoryInventory
+	memoryInventory = modules
+	memoryInventoryMu.Unlock()
+
+	t.Cleanup(func() {
+		memoryInventoryMu.Lock()
+		memoryInventory = previous
+		memoryInventoryMu.Unlock()
+	})
+}
+
+func TestEdacDimmLocator(t *testing.T) {
+	tests := []struct {
+		name      string
+		inventory []string
+		label     string
+		want      string
+	}{
+		{"exact match ignores case and separators", []string{"CPU0 DIMM A1", "CPU1 DIMM A1"}, "cpu1_dimm_a1", "CPU1 DIMM A1"},
+		{"unique suffix", []string{"DIMM_A1", "DIMM_B1"}, "CPU0_DIMM_A1", "DIMM_A1"},
+		// на двухсокетной плате слот DIMM_A1 есть у каждого процессора, и
+		// CPU0_DIMM_A1 и CPU1_DIMM_A1 совпадают с обоими по суффиксу
+		{"ambiguous suffix CPU0", []string{"DIMM_A1", "DIMM_A1"}, "CPU0_DIMM_A1", ""},
+		{"ambiguous suffix CPU1", []string{"DIMM_A1", "DIMM_A1"}, "CPU1_DIMM_A1", ""},
+		{"two different suffix matches", []string{"DIMM_A1", "A1"}, "CPU1_DIMM_A1", ""},
+		{"no match", []string{"A1"}, "CPU0_DIMM_B2", ""},
+		{"empty label", []string{"A1"}, "", ""},
+		{"empty inventory", nil, "CPU0_DIMM_A1", ""},
+	}
+
+	for _, tt := range tests {
+		setMemoryInventory(t, tt.inventory...)
+		if got := edacDimmLocator(tt.label); got != tt.want {
+			t.Errorf("%s: edacDimmLocator(%q) = %q, want %q", tt.name, tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestRecordEdacControllers(t *testing.T) {
+	EdacErrors.Reset()
+	EdacDimmErrors.Reset()
+	EdacCsrowErrors.Reset()
+	setMemoryInventory(t, "CPU0_DIMM_A1", "CPU0_DIMM_B1", "CPU1_DIMM_A1")
+
+	// непрочитанный dimm_ue_count у dimm1 не публикуется нулём
+	recordEdacControllers(readEdacControllers(filepath.Join("testdata", "edac", "dimm", "mc")))
+	want := []string{
+		"edac_dimm_errors_total{controller=mc0,dimm=dimm0,label=CPU0_DIMM_A1,locator=CPU0_DIMM_A1,type=correctable} 5",
+		"edac_dimm_errors_total{controller=mc0,dimm=dimm0,label=CPU0_DIMM_A1,locator=CPU0_DIMM_A1,type=uncorrectable} 0",
+		"edac_dimm_errors_total{controller=mc0,dimm=dimm1,label=CPU0_DIMM_B1,locator=CPU0_DIMM_B1,type=correctable} 0",
+		"edac_dimm_errors_total{controller=mc1,dimm=dimm0,label=CPU1_DIMM_A1,locator=CPU1_DIMM_A1,type=correctable} 0",
+		"edac_dimm_errors_total{controller=mc1,dimm=dimm0,label=CPU1_DIMM_A1,locator=CPU1_DIMM_A1,type=uncorrectable} 1",
+		"edac_errors_total{controller=mc0,type=correctable} 5",
+		"edac_errors_total{controller=mc0,type=uncorrectable} 0",
+		"edac_errors_total{controller=mc1,type=correctable} 0",
+		"edac_errors_total{controller=mc1,type=uncorrectable} 1",
+	}
+	if got := gatherSeries(t, EdacErrors, EdacDimmErrors, EdacCsrowErrors); !reflect.DeepEqual(got, want) {
+		t.Errorf("dimm layout:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// csrow публикуются, только если драйвер не ведёт учёт по DIMM
+	recordEdacControllers(readEdacControllers(filepath.Join("testdata", "edac", "csrow", "mc")))
+	want = []string{
+		"edac_csrow_errors_total{controller=mc0,csrow=csrow0,type=correctable} 12",
+		"edac_csrow_errors_total{controller=mc0,csrow=csrow0,type=uncorrectable} 0",
+		"edac_csrow_errors_total{controller=mc0,csrow=csrow1,type=correctable} 0",
+		"edac_csrow_errors_total{controller=mc0,csrow=csrow1,type=uncorrectable} 0",
+		"edac_csrow_errors_total{controller=mc1,csrow=csrow0,type=correctable} 2",
+		"edac_errors_total{controller=mc0,type=correctable} 12",
+		"edac_errors_total{controller=mc0,type=uncorrectable} 0",
+		"edac_errors_total{controller=mc1,type=correctable} 2",
+	}
+	if got := gatherSeries(t, EdacErrors, EdacDimmErrors, EdacCsrowErrors); !reflect.DeepEqual(got, want) {
+		t.Errorf("csrow layout:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
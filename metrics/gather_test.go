@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gatherSeries регистрирует collectors в отдельном реестре и возвращает их
+// серии в виде "имя{лейблы} значение", отсортированные по строке.
+func gatherSeries(t *testing.T, collectors ...prometheus.Collector) []string {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors...)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var series []string
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetName()+"="+label.GetValue())
+			}
+
+			value := metric.GetGauge().GetValue()
+			if metric.GetCounter() != nil {
+				value = metric.GetCounter().GetValue()
+			}
+			series = append(series, fmt.Sprintf("%s{%s} %g", family.GetName(), strings.Join(labels, ","), value))
+		}
+	}
+	sort.Strings(series)
+	return series
+}
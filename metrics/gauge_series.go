@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gaugeSeries запоминает серии GaugeVec, опубликованные за цикл сбора, и
+// удаляет серии прошлого цикла, которые не были обновлены. Так исчезнувшие
+// устройства и сменившиеся наборы лейблов не остаются с замороженными
+// значениями, а scrape во время сбора не видит пустой вектор, как при Reset.
+// Не потокобезопасен и используется из одной горутины сборщика.
+type gaugeSeries struct {
+	vec      *prometheus.GaugeVec
+	previous map[string]prometheus.Labels
+	current  map[string]prometheus.Labels
+}
+
+func newGaugeSeries(vec *prometheus.GaugeVec) *gaugeSeries {
+	return &gaugeSeries{
+		vec:      vec,
+		previous: make(map[string]prometheus.Labels),
+		current:  make(map[string]prometheus.Labels),
+	}
+}
+
+// Set публикует значение серии и отмечает её как обновлённую в этом цикле.
+func (s *gaugeSeries) Set(labels prometheus.Labels, value float64) {
+	s.vec.With(labels).Set(value)
+	s.current[labelsKey(labels)] = labels
+}
+
+// Flush завершает цикл: удаляет серии, не обновлённые с прошлого Flush.
+func (s *gaugeSeries) Flush() {
+	for key, labels := range s.previous {
+		if _, ok := s.current[key]; !ok {
+			s.vec.Delete(labels)
+		}
+	}
+	s.previous = s.current
+	s.current = make(map[string]prometheus.Labels, len(s.previous))
+}
+
+func labelsKey(labels prometheus.Labels) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(labels[name])
+		b.WriteByte('\xff')
+	}
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Метрики hwmon адресуются лейблами chip (уникальное имя устройства, например
+// coretemp.0 или 0000:01:00.0), chip_name (драйвер из файла name), sensor
+// (temp1, fan2, in0) и label (подпись датчика из *_label или имя sensor).
+var hwmonLabels = []string{"chip", "chip_name", "sensor", "label"}
+
+var (
+	HwmonTemperature         = newHwmonGaugeVec("hwmon_temperature_celsius", "Hardware monitor temperature in celsius")
+	HwmonTemperatureMax      = newHwmonGaugeVec("hwmon_temperature_max_celsius", "Hardware monitor temperature high threshold in celsius")
+	HwmonTemperatureCritical = newHwmonGaugeVec("hwmon_temperature_crit_celsius", "Hardware monitor temperature critical threshold in celsius")
+	HwmonFanSpeed            = newHwmonGaugeVec("hwmon_fan_rpm", "Hardware monitor fan speed in RPM")
+	HwmonFanMin              = newHwmonGaugeVec("hwmon_fan_min_rpm", "Hardware monitor fan minimum speed in RPM")
+	HwmonFanMax              = newHwmonGaugeVec("hwmon_fan_max_rpm", "Hardware monitor fan maximum speed in RPM")
+	HwmonVoltage             = newHwmonGaugeVec("hwmon_voltage_volts", "Hardware monitor voltage in volts")
+	HwmonVoltageMin          = newHwmonGaugeVec("hwmon_voltage_min_volts", "Hardware monitor voltage low threshold in volts")
+	HwmonVoltageMax          = newHwmonGaugeVec("hwmon_voltage_max_volts", "Hardware monitor voltage high threshold in volts")
+	HwmonCurrent             = newHwmonGaugeVec("hwmon_current_amperes", "Hardware monitor current in amperes")
+	HwmonCurrentMax          = newHwmonGaugeVec("hwmon_current_max_amperes", "Hardware monitor current high threshold in amperes")
+	HwmonCurrentCritical     = newHwmonGaugeVec("hwmon_current_crit_amperes", "Hardware monitor current critical threshold in amperes")
+	HwmonPower               = newHwmonGaugeVec("hwmon_power_watts", "Hardware monitor power in watts")
+	HwmonPowerMax            = newHwmonGaugeVec("hwmon_power_max_watts", "Hardware monitor power high threshold in watts")
+	HwmonPowerCritical       = newHwmonGaugeVec("hwmon_power_crit_watts", "Hardware monitor power critical threshold in watts")
+
+	HwmonAlarm = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "hwmon_sensor_alarm",
+			Help: "Hardware monitor alarm flag (1 = alarm raised); type = alarm/min_alarm/max_alarm/crit_alarm/fault",
+		},
+		append(append([]string{}, hwmonLabels...), "type"),
+	)
+)
+
+func newHwmonGaugeVec(name, help string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: name,
+			Help: help,
+		},
+		hwmonLabels,
+	)
+}
@@ -0,0 +1,285 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const hwmonSysfsRoot = "/sys/class/hwmon"
+
+// hwmonAttrPattern — атрибуты датчиков hwmon: temp1_input, fan2_min, in0_max...
+var hwmonAttrPattern = regexp.MustCompile(`^(temp|fan|in|curr|power)([0-9]+)_([a-z_]+)$`)
+
+// hwmonScale — делители для перевода значений sysfs в базовые единицы:
+// миллиградусы, милливольты, миллиамперы и микроватты.
+var hwmonScale = map[string]float64{
+	"temp":  1000,
+	"fan":   1,
+	"in":    1000,
+	"curr":  1000,
+	"power": 1000000,
+}
+
+var hwmonAlarmAttrs = map[string]bool{
+	"alarm":       true,
+	"min_alarm":   true,
+	"max_alarm":   true,
+	"crit_alarm":  true,
+	"lcrit_alarm": true,
+	"fault":       true,
+}
+
+// hwmonSensor — один датчик hwmon-чипа со всеми прочитанными атрибутами.
+type hwmonSensor struct {
+	Chip     string
+	ChipName string
+	Sensor   string
+	Kind     string
+	Label    string
+	Values   map[string]float64
+	Alarms   map[string]float64
+}
+
+// hwmonTarget — метрика для атрибутов датчика; первый найденный атрибут из
+// списка используется как значение.
+type hwmonTarget struct {
+	attrs  []string
+	series *gaugeSeries
+}
+
+// hwmonSeries — серии датчиков hwmon по типу датчика. Серии пропавших
+// датчиков и сброшенных тревог удаляются при Flush.
+type hwmonSeries struct {
+	targets map[string][]hwmonTarget
+	alarm   *gaugeSeries
+}
+
+func newHwmonSeries() *hwmonSeries {
+	return &hwmonSeries{
+		targets: map[string][]hwmonTarget{
+			"temp": {
+				{[]string{"input"}, newGaugeSeries(HwmonTemperature)},
+				{[]string{"max"}, newGaugeSeries(HwmonTemperatureMax)},
+				{[]string{"crit"}, newGaugeSeries(HwmonTemperatureCritical)},
+			},
+			"fan": {
+				{[]string{"input"}, newGaugeSeries(HwmonFanSpeed)},
+				{[]string{"min"}, newGaugeSeries(HwmonFanMin)},
+				{[]string{"max"}, newGaugeSeries(HwmonFanMax)},
+			},
+			"in": {
+				{[]string{"input"}, newGaugeSeries(HwmonVoltage)},
+				{[]string{"min"}, newGaugeSeries(HwmonVoltageMin)},
+				{[]string{"max"}, newGaugeSeries(HwmonVoltageMax)},
+			},
+			"curr": {
+				{[]string{"input"}, newGaugeSeries(HwmonCurrent)},
+				{[]string{"max"}, newGaugeSeries(HwmonCurrentMax)},
+				{[]string{"crit"}, newGaugeSeries(HwmonCurrentCritical)},
+			},
+			"power": {
+				{[]string{"input", "average"}, newGaugeSeries(HwmonPower)},
+				{[]string{"max", "cap"}, newGaugeSeries(HwmonPowerMax)},
+				{[]string{"crit"}, newGaugeSeries(HwmonPowerCritical)},
+			},
+		},
+		alarm: newGaugeSeries(HwmonAlarm),
+	}
+}
+
+func RecordHwmonMetrics() {
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		series := newHwmonSeries()
+
+		for {
+			recordHwmonSensors(series, readHwmonSensors(hwmonSysfsRoot))
+			<-ticker.C
+		}
+	}()
+}
+
+func recordHwmonSensors(series *hwmonSeries, sensors []hwmonSensor) {
+	for _, sensor := range sensors {
+		labels := prometheus.Labels{
+			"chip":      sensor.Chip,
+			"chip_name": sensor.ChipName,
+			"sensor":    sensor.Sensor,
+			"label":     sensor.Label,
+		}
+
+		for _, t := range series.targets[sensor.Kind] {
+			for _, attr := range t.attrs {
+				if value, ok := sensor.Values[attr]; ok {
+					t.series.Set(labels, value)
+					break
+				}
+			}
+		}
+
+		for alarm, value := range sensor.Alarms {
+			series.alarm.Set(prometheus.Labels{
+				"chip":      sensor.Chip,
+				"chip_name": sensor.ChipName,
+				"sensor":    sensor.Sensor,
+				"label":     sensor.Label,
+				"type":      alarm,
+			}, value)
+		}
+	}
+
+	for _, list := range series.targets {
+		for _, t := range list {
+			t.series.Flush()
+		}
+	}
+	series.alarm.Flush()
+}
+
+// readHwmonSensors обходит root (/sys/class/hwmon) и собирает атрибуты всех
+// датчиков. Старые драйверы кладут атрибуты в hwmonN/device, их тоже учитываем.
+func readHwmonSensors(root string) []hwmonSensor {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	chips := make([]hwmonChip, 0, len(entries))
+	for _, entry := range entries {
+		dir := filepath.Join(root, entry.Name())
+		chipName := readSysfsValue(filepath.Join(dir, "name"))
+		if chipName == "" {
+			chipName = readSysfsValue(filepath.Join(dir, "device", "name"))
+		}
+		chips = append(chips, hwmonChip{
+			Dir:    dir,
+			Hwmon:  entry.Name(),
+			Device: hwmonDevice(dir),
+			Name:   orUnknown(chipName),
+		})
+	}
+	assignHwmonChipIDs(chips)
+
+	var result []hwmonSensor
+	for _, c := range chips {
+		dir, chip, chipName := c.Dir, c.ID, c.Name
+
+		sensors := make(map[string]*hwmonSensor)
+		var order []string
+		for _, attrDir := range []string{dir, filepath.Join(dir, "device")} {
+			files, err := os.ReadDir(attrDir)
+			if err != nil {
+				continue
+			}
+
+			for _, file := range files {
+				match := hwmonAttrPattern.FindStringSubmatch(file.Name())
+				if match == nil {
+					continue
+				}
+				kind, sensorName, attr := match[1], match[1]+match[2], match[3]
+
+				sensor, ok := sensors[sensorName]
+				if !ok {
+					sensor = &hwmonSensor{
+						Chip:     chip,
+						ChipName: chipName,
+						Sensor:   sensorName,
+						Kind:     kind,
+						Values:   map[string]float64{},
+						Alarms:   map[string]float64{},
+					}
+					sensors[sensorName] = sensor
+					order = append(order, sensorName)
+				}
+
+				raw := readSysfsValue(filepath.Join(attrDir, file.Name()))
+				switch {
+				case attr == "label":
+					sensor.Label = raw
+				case hwmonAlarmAttrs[attr]:
+					if value, err := strconv.ParseFloat(raw, 64); err == nil {
+						sensor.Alarms[attr] = value
+					}
+				default:
+					if _, exists := sensor.Values[attr]; exists {
+						continue
+					}
+					if value, err := strconv.ParseFloat(raw, 64); err == nil {
+						sensor.Values[attr] = value / hwmonScale[kind]
+					}
+				}
+			}
+		}
+
+		for _, name := range order {
+			sensor := sensors[name]
+			if sensor.Label == "" {
+				sensor.Label = sensor.Sensor
+			}
+			result = append(result, *sensor)
+		}
+	}
+
+	return result
+}
+
+// hwmonChip — каталог hwmonN и устройство, к которому он привязан.
+type hwmonChip struct {
+	Dir    string
+	Hwmon  string
+	Device string
+	Name   string
+	ID     string
+}
+
+// hwmonDevice возвращает имя устройства, к которому привязан hwmon
+// (coretemp.0, 0000:01:00.0, nvme0), или пустую строку для виртуальных
+// датчиков без устройства.
+func hwmonDevice(dir string) string {
+	resolved, err := filepath.EvalSymlinks(filepath.Join(dir, "device"))
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(resolved)
+}
+
+// assignHwmonChipIDs задаёт чипам стабильные идентификаторы. Обычно это имя
+// устройства: номер hwmonN меняется между загрузками. Драйвер может
+// зарегистрировать на одном устройстве несколько hwmon (например, it87 для
+// двух чипов Super I/O), тогда к устройству добавляется имя чипа, а если
+// совпадает и оно — номер hwmonN. Датчики без устройства называются hwmonN.
+func assignHwmonChipIDs(chips []hwmonChip) {
+	devices := make(map[string]int)
+	names := make(map[string]int)
+	for _, c := range chips {
+		if c.Device == "" {
+			continue
+		}
+		devices[c.Device]++
+		names[c.Device+"/"+c.Name]++
+	}
+
+	for i := range chips {
+		c := &chips[i]
+		switch {
+		case c.Device == "":
+			c.ID = c.Hwmon
+		case devices[c.Device] == 1:
+			c.ID = c.Device
+		case names[c.Device+"/"+c.Name] == 1:
+			c.ID = c.Device + "/" + c.Name
+		default:
+			c.ID = c.Device + "/" + c.Hwmon
+		}
+	}
+}
@@ -0,0 +1,155 @@
+//go:build linux
+
+package metrics
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadHwmonSensors(t *testing.T) {
+	got := readHwmonSensors(filepath.Join("testdata", "hwmon", "class", "hwmon"))
+
+	want := []hwmonSensor{
+		{
+			Chip: "coretemp.0", ChipName: "coretemp", Sensor: "temp1", Kind: "temp", Label: "Package id 0",
+			Values: map[string]float64{"input": 45, "max": 80, "crit": 100},
+			Alarms: map[string]float64{"crit_alarm": 0},
+		},
+		{
+			Chip: "coretemp.0", ChipName: "coretemp", Sensor: "temp2", Kind: "temp", Label: "temp2",
+			Values: map[string]float64{"input": 43},
+			Alarms: map[string]float64{"crit_alarm": 1},
+		},
+		// два hwmon на одном устройстве различаются по имени чипа
+		{
+			Chip: "it87.2624/it8686", ChipName: "it8686", Sensor: "in0", Kind: "in", Label: "Vcore",
+			Values: map[string]float64{"input": 1.104},
+			Alarms: map[string]float64{},
+		},
+		{
+			Chip: "it87.2624/it8686", ChipName: "it8686", Sensor: "power1", Kind: "power", Label: "power1",
+			Values: map[string]float64{"average": 35, "cap": 200},
+			Alarms: map[string]float64{},
+		},
+		{
+			Chip: "it87.2624/it8792", ChipName: "it8792", Sensor: "temp1", Kind: "temp", Label: "System",
+			Values: map[string]float64{"input": 52},
+			Alarms: map[string]float64{},
+		},
+		// старый драйвер: имя и атрибуты в hwmonN/device
+		{
+			Chip: "w83627ehf.656", ChipName: "w83627ehf", Sensor: "curr1", Kind: "curr", Label: "curr1",
+			Values: map[string]float64{"input": 1.5},
+			Alarms: map[string]float64{},
+		},
+		{
+			Chip: "w83627ehf.656", ChipName: "w83627ehf", Sensor: "fan1", Kind: "fan", Label: "fan1",
+			Values: map[string]float64{"input": 1200, "min": 600, "div": 8},
+			Alarms: map[string]float64{"alarm": 0},
+		},
+		// виртуальный датчик без устройства
+		{
+			Chip: "hwmon4", ChipName: "acpitz", Sensor: "temp1", Kind: "temp", Label: "temp1",
+			Values: map[string]float64{"input": 27.8},
+			Alarms: map[string]float64{},
+		},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("readHwmonSensors() returned %d sensors, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("sensor %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRecordHwmonSensors(t *testing.T) {
+	HwmonTemperature.Reset()
+	HwmonTemperatureCritical.Reset()
+	HwmonFanSpeed.Reset()
+	HwmonAlarm.Reset()
+
+	gather := func() []string {
+		return gatherSeries(t, HwmonTemperature, HwmonTemperatureCritical, HwmonFanSpeed, HwmonAlarm)
+	}
+
+	series := newHwmonSeries()
+	recordHwmonSensors(series, []hwmonSensor{
+		{
+			Chip: "coretemp.0", ChipName: "coretemp", Sensor: "temp1", Kind: "temp", Label: "Package id 0",
+			Values: map[string]float64{"input": 45, "crit": 100},
+			Alarms: map[string]float64{"crit_alarm": 0},
+		},
+		{
+			Chip: "nct6775.656", ChipName: "nct6798", Sensor: "fan2", Kind: "fan", Label: "fan2",
+			Values: map[string]float64{"input": 1200},
+			Alarms: map[string]float64{"alarm": 1},
+		},
+	})
+	want := []string{
+		"hwmon_fan_rpm{chip=nct6775.656,chip_name=nct6798,label=fan2,sensor=fan2} 1200",
+		"hwmon_sensor_alarm{chip=coretemp.0,chip_name=coretemp,label=Package id 0,sensor=temp1,type=crit_alarm} 0",
+		"hwmon_sensor_alarm{chip=nct6775.656,chip_name=nct6798,label=fan2,sensor=fan2,type=alarm} 1",
+		"hwmon_temperature_celsius{chip=coretemp.0,chip_name=coretemp,label=Package id 0,sensor=temp1} 45",
+		"hwmon_temperature_crit_celsius{chip=coretemp.0,chip_name=coretemp,label=Package id 0,sensor=temp1} 100",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("first cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// модуль nct6775 выгружен: его серии удаляются, остальные обновляются
+	recordHwmonSensors(series, []hwmonSensor{
+		{
+			Chip: "coretemp.0", ChipName: "coretemp", Sensor: "temp1", Kind: "temp", Label: "Package id 0",
+			Values: map[string]float64{"input": 47, "crit": 100},
+			Alarms: map[string]float64{"crit_alarm": 0},
+		},
+	})
+	want = []string{
+		"hwmon_sensor_alarm{chip=coretemp.0,chip_name=coretemp,label=Package id 0,sensor=temp1,type=crit_alarm} 0",
+		"hwmon_temperature_celsius{chip=coretemp.0,chip_name=coretemp,label=Package id 0,sensor=temp1} 47",
+		"hwmon_temperature_crit_celsius{chip=coretemp.0,chip_name=coretemp,label=Package id 0,sensor=temp1} 100",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("second cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestReadHwmonSensorsMissingRoot(t *testing.T) {
+	if got := readHwmonSensors(filepath.Join("testdata", "hwmon", "missing")); got != nil {
+		t.Errorf("readHwmonSensors(missing) = %+v, want nil", got)
+	}
+}
+
+func TestAssignHwmonChipIDs(t *testing.T) {
+	chips := []hwmonChip{
+		{Hwmon: "hwmon0", Device: "coretemp.0", Name: "coretemp"},
+		{Hwmon: "hwmon1", Device: "it87.2624", Name: "it8686"},
+		{Hwmon: "hwmon2", Device: "it87.2624", Name: "it8792"},
+		{Hwmon: "hwmon3", Device: "0000:03:00.0", Name: "amdgpu"},
+		{Hwmon: "hwmon4", Device: "0000:03:00.0", Name: "amdgpu"},
+		{Hwmon: "hwmon5", Name: "acpitz"},
+		{Hwmon: "hwmon6", Name: "acpitz"},
+	}
+	assignHwmonChipIDs(chips)
+
+	want := []string{
+		"coretemp.0",
+		"it87.2624/it8686",
+		"it87.2624/it8792",
+		"0000:03:00.0/hwmon3",
+		"0000:03:00.0/hwmon4",
+		"hwmon5",
+		"hwmon6",
+	}
+	for i, chip := range chips {
+		if chip.ID != want[i] {
+			t.Errorf("%s: ID = %q, want %q", chip.Hwmon, chip.ID, want[i])
+		}
+	}
+}
@@ -0,0 +1,167 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type socketOwner struct {
+	PID     int
+	Process string
+}
+
+type listenSocket struct {
+	Proto   string
+	Address string
+	Port    string
+	UID     string
+	Inode   string
+}
+
+// RecordListeningPorts публикует все слушающие TCP/UDP-сокеты с процессом-
+// владельцем. Первый проход запоминает уже открытые порты, дальше каждый новый
+// порт увеличивает network_listen_new_total.
+func RecordListeningPorts() {
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		var known map[string]bool
+		users := make(map[string]string)
+		series := newGaugeSeries(NetworkListenInfo)
+
+		for {
+			sockets := readListeningSockets("/proc/net")
+			owners := socketOwners()
+
+			current := make(map[string]bool, len(sockets))
+
+			for _, sock := range sockets {
+				owner := owners[sock.Inode]
+				pid := ""
+				if owner.PID > 0 {
+					pid = strconv.Itoa(owner.PID)
+				}
+
+				series.Set(prometheus.Labels{
+					"proto":   sock.Proto,
+					"address": sock.Address,
+					"port":    sock.Port,
+					"process": orUnknown(owner.Process),
+					"pid":     orUnknown(pid),
+					"user":    lookupUserName(users, sock.UID),
+				}, 1)
+
+				key := sock.Proto + "|" + sock.Address + "|" + sock.Port
+				current[key] = true
+				if known == nil {
+					NetworkNewListeners.With(prometheus.Labels{"proto": sock.Proto}).Add(0)
+				} else if !known[key] {
+					NetworkNewListeners.With(prometheus.Labels{"proto": sock.Proto}).Inc()
+				}
+			}
+
+			// закрытые порты и прежние процессы-владельцы удаляются
+			series.Flush()
+			known = current
+			<-ticker.C
+		}
+	}()
+}
+
+// readListeningSockets возвращает TCP-сокеты в состоянии LISTEN и несвязанные
+// UDP-сокеты (без удалённого адреса), принимающие датаграммы. Порт не
+// фильтруется: службы вроде WireGuard слушают порты из эфемерного диапазона.
+func readListeningSockets(procNet string) []listenSocket {
+	var result []listenSocket
+
+	for _, proto := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		data, err := os.ReadFile(filepath.Join(procNet, proto))
+		if err != nil {
+			continue
+		}
+
+		for _, sock := range parseProcNetSockets(string(data)) {
+			if strings.HasPrefix(proto, "tcp") && sock.State != "LISTEN" {
+				continue
+			}
+			if strings.HasPrefix(proto, "udp") && (sock.RemotePort != 0 || !sock.RemoteIP.IsUnspecified()) {
+				continue
+			}
+
+			result = append(result, listenSocket{
+				Proto:   proto,
+				Address: sock.LocalIP.String(),
+				Port:    strconv.Itoa(int(sock.LocalPort)),
+				UID:     sock.UID,
+				Inode:   sock.Inode,
+			})
+		}
+	}
+
+	return result
+}
+
+// socketOwners сопоставляет inode сокета с процессом по ссылкам
+// /proc/<pid>/fd/N -> socket:[inode]. Без root видны только собственные процессы.
+func socketOwners() map[string]socketOwner {
+	owners := make(map[string]socketOwner)
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return owners
+	}
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+
+		process := ""
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			if _, ok := owners[inode]; ok {
+				continue
+			}
+			if process == "" {
+				process = readSysfsValue(filepath.Join("/proc", entry.Name(), "comm"))
+			}
+			owners[inode] = socketOwner{PID: pid, Process: process}
+		}
+	}
+
+	return owners
+}
+
+func lookupUserName(cache map[string]string, uid string) string {
+	if name, ok := cache[uid]; ok {
+		return name
+	}
+
+	name := uid
+	if u, err := user.LookupId(uid); err == nil {
+		name = u.Username
+	}
+	cache[uid] = name
+	return name
+}
@@ -0,0 +1,26 @@
+//go:build linux
+
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadListeningSockets(t *testing.T) {
+	// подключённые TCP- и UDP-сокеты не считаются слушающими; udp6 в
+	// testdata/procnet нет
+	got := readListeningSockets("testdata/procnet")
+	want := []listenSocket{
+		{Proto: "tcp", Address: "127.0.0.1", Port: "631", UID: "0", Inode: "23811"},
+		{Proto: "tcp", Address: "0.0.0.0", Port: "22", UID: "0", Inode: "21035"},
+		{Proto: "tcp6", Address: "::", Port: "22", UID: "0", Inode: "21037"},
+		{Proto: "tcp6", Address: "::1", Port: "631", UID: "0", Inode: "23810"},
+		{Proto: "udp", Address: "127.0.0.53", Port: "53", UID: "991", Inode: "20512"},
+		// WireGuard слушает порт из эфемерного диапазона
+		{Proto: "udp", Address: "0.0.0.0", Port: "51820", UID: "0", Inode: "31644"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readListeningSockets() = %+v, want %+v", got, want)
+	}
+}
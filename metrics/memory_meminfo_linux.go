@@ -0,0 +1,149 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// meminfoBreakdown — поля /proc/meminfo, публикуемые в memory_breakdown_bytes.
+var meminfoBreakdown = map[string]string{
+	"Buffers":      "buffers",
+	"Cached":       "cached",
+	"SwapCached":   "swap_cached",
+	"Active":       "active",
+	"Inactive":     "inactive",
+	"Slab":         "slab",
+	"SReclaimable": "slab_reclaimable",
+	"SUnreclaim":   "slab_unreclaimable",
+	"Shmem":        "shared",
+	"Dirty":        "dirty",
+	"Writeback":    "writeback",
+	"Mapped":       "mapped",
+	"AnonPages":    "anon",
+	"PageTables":   "page_tables",
+	"Committed_AS": "committed_as",
+	"CommitLimit":  "commit_limit",
+	"Hugepagesize": "hugepage_size",
+}
+
+func RecordMemoryBreakdown() {
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			if data, err := os.ReadFile("/proc/meminfo"); err == nil {
+				recordMeminfo(parseMeminfo(string(data)))
+			}
+			if data, err := os.ReadFile("/proc/vmstat"); err == nil {
+				recordVmstat(parseVmstat(string(data)))
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+func recordMeminfo(meminfo map[string]float64) {
+	for field, name := range meminfoBreakdown {
+		if value, ok := meminfo[field]; ok {
+			MemoryBreakdown.With(prometheus.Labels{"type": name}).Set(value)
+		}
+	}
+
+	// HugePages_* указаны в страницах, размер страницы — в Hugepagesize
+	if size, ok := meminfo["Hugepagesize"]; ok {
+		for field, name := range map[string]string{
+			"HugePages_Total": "hugepages_total",
+			"HugePages_Free":  "hugepages_free",
+			"HugePages_Rsvd":  "hugepages_reserved",
+			"HugePages_Surp":  "hugepages_surplus",
+		} {
+			if pages, ok := meminfo[field]; ok {
+				MemoryBreakdown.With(prometheus.Labels{"type": name}).Set(pages * size)
+			}
+		}
+	}
+
+	if value, ok := meminfo["SwapTotal"]; ok {
+		SwapTotal.Set(value)
+	}
+	if value, ok := meminfo["SwapFree"]; ok {
+		SwapFree.Set(value)
+	}
+}
+
+// recordVmstat публикует счётчики /proc/vmstat как абсолютные значения,
+// накопленные с загрузки.
+func recordVmstat(vmstat map[string]uint64) {
+	set := func(key string, counter *ConstCounterVec, labels prometheus.Labels) {
+		if value, ok := vmstat[key]; ok {
+			counter.Set(labels, float64(value))
+		}
+	}
+
+	set("pswpin", SwapPages, prometheus.Labels{"direction": "in"})
+	set("pswpout", SwapPages, prometheus.Labels{"direction": "out"})
+	set("pgmajfault", PageFaults, prometheus.Labels{"type": "major"})
+	set("oom_kill", OOMKills, nil)
+
+	// pgfault включает и major faults
+	if total, ok := vmstat["pgfault"]; ok {
+		var minor uint64
+		if major := vmstat["pgmajfault"]; total > major {
+			minor = total - major
+		}
+		PageFaults.Set(prometheus.Labels{"type": "minor"}, float64(minor))
+	}
+}
+
+// parseMeminfo разбирает /proc/meminfo; значения с единицей kB переводятся в
+// байты, счётчики страниц (HugePages_*) возвращаются как есть.
+func parseMeminfo(data string) map[string]float64 {
+	result := make(map[string]float64)
+
+	for _, line := range strings.Split(data, "\n") {
+		key, rest, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			value *= 1024
+		}
+		result[key] = value
+	}
+
+	return result
+}
+
+// parseVmstat разбирает /proc/vmstat: "pgfault 123456".
+func parseVmstat(data string) map[string]uint64 {
+	result := make(map[string]uint64)
+
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			result[fields[0]] = value
+		}
+	}
+
+	return result
+}
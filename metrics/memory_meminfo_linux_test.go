@@ -0,0 +1,59 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readProcFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "proc", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseMeminfo(t *testing.T) {
+	got := parseMeminfo(readProcFixture(t, "meminfo"))
+
+	for field, want := range map[string]float64{
+		"MemTotal":        8023436 * 1024,
+		"Committed_AS":    5410212 * 1024,
+		"Hugepagesize":    2048 * 1024,
+		"HugePages_Total": 4, // в страницах, без kB
+		"Writeback":       0,
+	} {
+		if got[field] != want {
+			t.Errorf("%s = %v, want %v", field, got[field], want)
+		}
+	}
+	if len(got) != 26 {
+		t.Errorf("parsed %d fields, want 26", len(got))
+	}
+}
+
+func TestRecordVmstat(t *testing.T) {
+	SwapPages.Reset()
+	PageFaults.Reset()
+	OOMKills.Reset()
+
+	// значения с загрузки публикуются сразу, а не приращения с запуска агента
+	recordVmstat(parseVmstat(readProcFixture(t, "vmstat")))
+	want := []string{
+		"oom_kills_total{} 2",
+		"page_faults_total{type=major} 12344",
+		"page_faults_total{type=minor} 9.1809966e+07",
+		"swap_pages_total{direction=in} 812",
+		"swap_pages_total{direction=out} 4096",
+	}
+	if got := gatherSeries(t, SwapPages, PageFaults, OOMKills); !reflect.DeepEqual(got, want) {
+		t.Errorf("series:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -8,7 +8,7 @@ var (
 			Name: "memory_module_info",
 			Help: "Memory module information",
 		},
-		[]string{"capacity", "manufacturer", "part_number", "serial_number", "speed"},
+		[]string{"capacity", "manufacturer", "part_number", "serial_number", "speed", "locator"},
 	)
 
 	TotalMemory = prometheus.NewGauge(
@@ -25,10 +25,60 @@ var (
 		},
 	)
 
+	// FreeMemory — полностью свободная память (MemFree). Память, доступная
+	// приложениям с учётом освобождаемого кеша, публикуется в AvailableMemory.
 	FreeMemory = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "free_memory_bytes",
-			Help: "Free memory on system",
+			Help: "Unused memory on system (MemFree); see available_memory_bytes for memory available to applications",
 		},
 	)
+
+	AvailableMemory = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "available_memory_bytes",
+			Help: "Memory available for starting new applications without swapping, including reclaimable cache",
+		},
+	)
+
+	MemoryBreakdown = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "memory_breakdown_bytes",
+			Help: "Memory usage breakdown from /proc/meminfo (buffers, cached, slab, shared, dirty, writeback, committed_as, hugepages...)",
+		},
+		[]string{"type"},
+	)
+
+	SwapTotal = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "swap_total_bytes",
+			Help: "Total swap space",
+		},
+	)
+
+	SwapFree = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "swap_free_bytes",
+			Help: "Unused swap space",
+		},
+	)
+
+	// счётчики /proc/vmstat ведутся ядром с загрузки
+	SwapPages = newConstCounterVec(
+		"swap_pages_total",
+		"Number of pages swapped in or out since boot (direction = in/out)",
+		[]string{"direction"},
+	)
+
+	PageFaults = newConstCounterVec(
+		"page_faults_total",
+		"Number of page faults since boot (type = minor/major)",
+		[]string{"type"},
+	)
+
+	OOMKills = newConstCounterVec(
+		"oom_kills_total",
+		"Number of processes killed by the OOM killer since boot",
+		nil,
+	)
 )
@@ -6,17 +6,27 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/shirou/gopsutil/mem"
 )
 
+// memoryInventory — модули памяти, найденные RecordMemoryModuleInfo; используется
+// для сопоставления ошибок EDAC со слотами.
+var (
+	memoryInventory   []memoryModule
+	memoryInventoryMu sync.RWMutex
+)
+
 type memoryModule struct {
 	SizeBytes    uint64
+	Locator      string
 	Manufacturer string
 	PartNumber   string
 	SerialNumber string
@@ -43,10 +53,15 @@ func RecordMemoryModuleInfo() {
 			"part_number":   "unknown",
 			"serial_number": "unknown",
 			"speed":         "unknown",
+			"locator":       "unknown",
 		}).Set(capacityGB)
 		return
 	}
 
+	memoryInventoryMu.Lock()
+	memoryInventory = modules
+	memoryInventoryMu.Unlock()
+
 	for _, module := range modules {
 		capacityGB := float64(module.SizeBytes) / (1024 * 1024 * 1024)
 		MemoryModuleInfo.With(prometheus.Labels{
@@ -55,6 +70,7 @@ func RecordMemoryModuleInfo() {
 			"part_number":   module.PartNumber,
 			"serial_number": module.SerialNumber,
 			"speed":         module.Speed,
+			"locator":       orUnknown(module.Locator),
 		}).Set(capacityGB)
 	}
 }
@@ -74,7 +90,8 @@ func RecordMemoryUsage() {
 
 			TotalMemory.Set(float64(stats.Total))
 			UsedMemory.Set(float64(stats.Used))
-			FreeMemory.Set(float64(stats.Available))
+			FreeMemory.Set(float64(stats.Free))
+			AvailableMemory.Set(float64(stats.Available))
 
 			<-ticker.C
 		}
@@ -85,10 +102,17 @@ func parseMemoryModules() ([]memoryModule, error) {
 	var modules []memoryModule
 	var errs []error
 
-	if dmidecode, err := parseMemoryModulesFromDmidecode(); err == nil && len(dmidecode) > 0 {
-		modules = dmidecode
-	} else {
-		if err != nil {
+	// таблицы SMBIOS читаются напрямую, без dmidecode/lshw
+	if tables, err := loadSMBIOS(); err == nil {
+		modules = tables.memoryModules()
+	} else if !os.IsNotExist(err) {
+		errs = append(errs, err)
+	}
+
+	if len(modules) == 0 {
+		if dmidecode, err := parseMemoryModulesFromDmidecode(); err == nil && len(dmidecode) > 0 {
+			modules = dmidecode
+		} else if err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -162,6 +186,8 @@ func parseMemoryModulesFromDmidecode() ([]memoryModule, error) {
 						}
 					}
 				}
+			case "Locator":
+				module.Locator = value
 			case "Manufacturer":
 				module.Manufacturer = sanitizeMemoryField(value)
 			case "Part Number":
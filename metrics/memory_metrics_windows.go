@@ -13,21 +13,22 @@ import (
 )
 
 type Win32_PhysicalMemory struct {
-	Capacity     uint64
-	Manufacturer string
-	PartNumber   string
-	SerialNumber string
-	Speed        uint32
+	Capacity      uint64
+	Manufacturer  string
+	PartNumber    string
+	SerialNumber  string
+	Speed         uint32
+	DeviceLocator string
 }
 
 // GetMemoryModules retrieves information about physical memory modules in the system
 // by querying the Win32_PhysicalMemory WMI class. It returns a slice of
 // Win32_PhysicalMemory structs containing details such as capacity, manufacturer,
-// part number, serial number, and speed, or an error if the query fails.
+// part number, serial number, speed and slot locator, or an error if the query fails.
 
 func GetMemoryModules() ([]Win32_PhysicalMemory, error) {
 	var memModules []Win32_PhysicalMemory
-	err := wmi.Query("SELECT Capacity, Manufacturer, PartNumber, SerialNumber, Speed FROM Win32_PhysicalMemory", &memModules)
+	err := wmi.Query("SELECT Capacity, Manufacturer, PartNumber, SerialNumber, Speed, DeviceLocator FROM Win32_PhysicalMemory", &memModules)
 	if err != nil {
 		return nil, fmt.Errorf("error getting memory modules info: %v", err)
 	}
@@ -47,7 +48,7 @@ func GetMemoryUsage() (*mem.VirtualMemoryStat, error) {
 
 // RecordMemoryModuleInfo records information about physical memory modules in the system
 // to prometheus metrics. It records the capacity in GB, manufacturer, part number, serial
-// number, and speed of each module. It runs in a separate goroutine and updates the
+// number, speed and slot locator of each module. It runs in a separate goroutine and updates the
 // metrics every 5 seconds.
 func RecordMemoryModuleInfo() {
 	modules, err := GetMemoryModules()
@@ -66,12 +67,14 @@ func RecordMemoryModuleInfo() {
 			"part_number":   module.PartNumber,
 			"serial_number": module.SerialNumber,
 			"speed":         fmt.Sprintf("%dMhz", module.Speed),
+			"locator":       orUnknown(module.DeviceLocator),
 		}).Set(memoryInGb)
 	}
 }
 
 // RecordMemoryUsage records virtual memory usage on the system in prometheus metrics.
-// It records total, used, and free memory in bytes. It runs in a separate goroutine
+// It records total, used, free and available memory in bytes (on Windows free
+// and available both report the available physical memory). It runs in a separate goroutine
 // and updates the metrics every 5 seconds.
 
 func RecordMemoryUsage() {
@@ -85,7 +88,8 @@ func RecordMemoryUsage() {
 
 			TotalMemory.Set(float64(memStat.Total))
 			UsedMemory.Set(float64(memStat.Used))
-			FreeMemory.Set(float64(memStat.Available))
+			FreeMemory.Set(float64(memStat.Free))
+			AvailableMemory.Set(float64(memStat.Available))
 
 			time.Sleep(5 * time.Second)
 		}
@@ -3,6 +3,8 @@ package metrics
 import (
 	"log"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"node_exporter_custom/internal/mockconfig"
 )
 
@@ -20,11 +22,22 @@ func ApplyMockConfig(cfg mockconfig.Config) {
 	totalSpace := float64(1000000000000)
 	freeSpace := totalSpace * float64(cfg.DiskFreePercent) / 100.0
 	usedSpace := totalSpace - freeSpace
-	DiskUsage.WithLabelValues("C:", "Mock Disk", "SSD").Set(usedSpace)
-	DiskUsagePercent.WithLabelValues("C:", "Mock Disk").Set(100.0 - float64(cfg.DiskFreePercent))
+	mockDisk := diskLabelSet{
+		Device:     "mock0",
+		Mountpoint: "C:",
+		Model:      "Mock Disk",
+		Serial:     "MOCK-0001",
+		MediaType:  "SSD",
+	}
+	mockLegacy := prometheus.Labels{"disk": "C:"}
+	DiskUsage.With(mockDisk.labels(
+		prometheus.Labels{"usage": "used"},
+		prometheus.Labels{"disk": "C:", "type": "used"},
+	)).Set(usedSpace)
+	DiskUsagePercent.With(mockDisk.labels(nil, mockLegacy)).Set(100.0 - float64(cfg.DiskFreePercent))
 
-	DiskReadBytes.WithLabelValues("C:", "Mock Disk").Set(float64(cfg.DiskReadBytesPerSec))
-	DiskWriteBytes.WithLabelValues("C:", "Mock Disk").Set(float64(cfg.DiskWriteBytesPerSec))
+	DiskReadBytes.With(mockDisk.labels(nil, mockLegacy)).Set(float64(cfg.DiskReadBytesPerSec))
+	DiskWriteBytes.With(mockDisk.labels(nil, mockLegacy)).Set(float64(cfg.DiskWriteBytesPerSec))
 
 	totalMemory := float64(16000000000)
 	freeMemory := totalMemory * float64(cfg.MemoryFreePercent) / 100.0
@@ -32,9 +45,10 @@ func ApplyMockConfig(cfg mockconfig.Config) {
 
 	TotalMemory.Set(totalMemory)
 	FreeMemory.Set(freeMemory)
+	AvailableMemory.Set(freeMemory)
 	UsedMemory.Set(usedMemory)
 
-	NetworkErrors.WithLabelValues("Mock Ethernet").Add(float64(cfg.NetworkErrors))
+	NetworkErrors.WithLabelValues("mock0", "Mock Ethernet").Add(float64(cfg.NetworkErrors))
 }
 
 func LogMockEnabled() {
@@ -9,13 +9,13 @@ import (
 )
 
 func RecordMotherboardInfo() {
-	manufacturer := readDMIField("board_vendor")
-	product := readDMIField("board_name")
-	serial := readDMIField("board_serial")
-	version := readDMIField("board_version")
+	manufacturer := dmiField("board_vendor")
+	product := dmiField("board_name")
+	serial := dmiField("board_serial")
+	version := dmiField("board_version")
 
 	if manufacturer == "" && product == "" && serial == "" {
-		log.Printf("baseboard information unavailable from /sys/class/dmi/id")
+		log.Printf("baseboard information unavailable from /sys/class/dmi/id and SMBIOS tables")
 	}
 
 	MotherboardInfo.With(prometheus.Labels{
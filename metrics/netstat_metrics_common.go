@@ -0,0 +1,55 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	NetworkTCPConnections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_tcp_connections",
+			Help: "Number of TCP sockets (IPv4 and IPv6) by connection state",
+		},
+		[]string{"state"},
+	)
+
+	NetworkSockets = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_sockets",
+			Help: "Number of sockets by protocol and state from /proc/net/sockstat",
+		},
+		[]string{"protocol", "state"},
+	)
+)
+
+// Счётчики протоколов ведёт ядро с момента загрузки, агент публикует их
+// абсолютные значения.
+var (
+	NetworkTCPEvents = newConstCounterVec(
+		"network_tcp_events_total",
+		"TCP protocol events since boot: opens, resets, retransmits, listen overflows/drops, timeouts",
+		[]string{"event"},
+	)
+
+	NetworkUDPEvents = newConstCounterVec(
+		"network_udp_events_total",
+		"UDP protocol events since boot: datagrams, receive/send errors, buffer errors, no port",
+		[]string{"protocol", "event"},
+	)
+)
+
+var (
+	NetworkListenInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_listen_info",
+			Help: "Listening TCP/UDP socket with owning process (value is always 1)",
+		},
+		[]string{"proto", "address", "port", "process", "pid", "user"},
+	)
+
+	NetworkNewListeners = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "network_listen_new_total",
+			Help: "Number of listening ports opened since agent start",
+		},
+		[]string{"proto"},
+	)
+)
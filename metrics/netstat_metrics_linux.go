@@ -0,0 +1,144 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// поля /proc/net/snmp (Tcp) и /proc/net/netstat (TcpExt) -> event
+	tcpSNMPEvents = map[string]string{
+		"ActiveOpens":  "active_opens",
+		"PassiveOpens": "passive_opens",
+		"AttemptFails": "attempt_fails",
+		"EstabResets":  "established_resets",
+		"InSegs":       "in_segments",
+		"OutSegs":      "out_segments",
+		"RetransSegs":  "retransmitted_segments",
+		"InErrs":       "in_errors",
+		"OutRsts":      "out_resets",
+	}
+
+	tcpExtEvents = map[string]string{
+		"ListenOverflows": "listen_overflows",
+		"ListenDrops":     "listen_drops",
+		"SyncookiesSent":  "syncookies_sent",
+		"TCPTimeouts":     "timeouts",
+		"TCPAbortOnData":  "aborts_on_data",
+	}
+
+	udpSNMPEvents = map[string]string{
+		"InDatagrams":  "in_datagrams",
+		"OutDatagrams": "out_datagrams",
+		"NoPorts":      "no_ports",
+		"InErrors":     "in_errors",
+		"RcvbufErrors": "receive_buffer_errors",
+		"SndbufErrors": "send_buffer_errors",
+	}
+)
+
+func RecordNetstatMetrics() {
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			recordTCPConnectionStates()
+			recordProtocolCounters()
+			recordSockstat()
+
+			<-ticker.C
+		}
+	}()
+}
+
+func recordTCPConnectionStates() {
+	counts := make(map[string]int)
+	for _, state := range tcpStates {
+		counts[state] = 0
+	}
+
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		for _, sock := range parseProcNetSockets(string(data)) {
+			counts[sock.State]++
+		}
+	}
+
+	for state, count := range counts {
+		NetworkTCPConnections.With(prometheus.Labels{"state": state}).Set(float64(count))
+	}
+}
+
+func recordProtocolCounters() {
+	snmp := map[string]map[string]int64{}
+	if data, err := os.ReadFile("/proc/net/snmp"); err == nil {
+		snmp = parseProcNetSNMP(string(data))
+	}
+	netstat := map[string]map[string]int64{}
+	if data, err := os.ReadFile("/proc/net/netstat"); err == nil {
+		netstat = parseProcNetSNMP(string(data))
+	}
+	snmp6 := map[string]int64{}
+	if data, err := os.ReadFile("/proc/net/snmp6"); err == nil {
+		snmp6 = parseProcNetSNMP6(string(data))
+	}
+
+	setProtocolCounters(snmp, netstat, snmp6)
+}
+
+// setProtocolCounters публикует счётчики протоколов. Их ведёт ядро с момента
+// загрузки, поэтому публикуются абсолютные значения, а не приращения с
+// запуска агента.
+func setProtocolCounters(snmp, netstat map[string]map[string]int64, snmp6 map[string]int64) {
+	tcp := NetworkTCPEvents.Batch()
+	udp := NetworkUDPEvents.Batch()
+	set := func(batch *constCounterBatch, labels prometheus.Labels, value int64) {
+		if value >= 0 {
+			batch.Set(labels, float64(value))
+		}
+	}
+
+	for field, event := range tcpSNMPEvents {
+		if value, ok := snmp["Tcp"][field]; ok {
+			set(tcp, prometheus.Labels{"event": event}, value)
+		}
+	}
+	for field, event := range tcpExtEvents {
+		if value, ok := netstat["TcpExt"][field]; ok {
+			set(tcp, prometheus.Labels{"event": event}, value)
+		}
+	}
+	for field, event := range udpSNMPEvents {
+		if value, ok := snmp["Udp"][field]; ok {
+			set(udp, prometheus.Labels{"protocol": "udp", "event": event}, value)
+		}
+		if value, ok := snmp6["Udp6"+field]; ok {
+			set(udp, prometheus.Labels{"protocol": "udp6", "event": event}, value)
+		}
+	}
+
+	tcp.Commit()
+	udp.Commit()
+}
+
+func recordSockstat() {
+	for _, path := range []string{"/proc/net/sockstat", "/proc/net/sockstat6"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		for proto, states := range parseProcNetSockstat(string(data)) {
+			for state, value := range states {
+				NetworkSockets.With(prometheus.Labels{"protocol": proto, "state": state}).Set(float64(value))
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+//go:build linux
+
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetProtocolCounters(t *testing.T) {
+	NetworkTCPEvents.Reset()
+	NetworkUDPEvents.Reset()
+
+	snmp := parseProcNetSNMP(readProcNetFixture(t, "snmp"))
+	netstat := parseProcNetSNMP(readProcNetFixture(t, "netstat"))
+	snmp6 := parseProcNetSNMP6(readProcNetFixture(t, "snmp6"))
+
+	// значения с момента загрузки публикуются сразу, без ожидания приращения
+	setProtocolCounters(snmp, netstat, snmp6)
+	want := []string{
+		"network_tcp_events_total{event=aborts_on_data} 141",
+		"network_tcp_events_total{event=active_opens} 48213",
+		"network_tcp_events_total{event=attempt_fails} 3561",
+		"network_tcp_events_total{event=established_resets} 812",
+		"network_tcp_events_total{event=in_errors} 7",
+		"network_tcp_events_total{event=in_segments} 1.708221e+06",
+		"network_tcp_events_total{event=listen_drops} 19",
+		"network_tcp_events_total{event=listen_overflows} 17",
+		"network_tcp_events_total{event=out_resets} 9914",
+		"network_tcp_events_total{event=out_segments} 1.822405e+06",
+		"network_tcp_events_total{event=passive_opens} 1290",
+		"network_tcp_events_total{event=retransmitted_segments} 4120",
+		"network_tcp_events_total{event=syncookies_sent} 2",
+		"network_tcp_events_total{event=timeouts} 388",
+		"network_udp_events_total{event=in_datagrams,protocol=udp6} 4211",
+		"network_udp_events_total{event=in_datagrams,protocol=udp} 93210",
+		"network_udp_events_total{event=in_errors,protocol=udp6} 0",
+		"network_udp_events_total{event=in_errors,protocol=udp} 3",
+		"network_udp_events_total{event=no_ports,protocol=udp6} 9",
+		"network_udp_events_total{event=no_ports,protocol=udp} 212",
+		"network_udp_events_total{event=out_datagrams,protocol=udp6} 4388",
+		"network_udp_events_total{event=out_datagrams,protocol=udp} 95112",
+		"network_udp_events_total{event=receive_buffer_errors,protocol=udp6} 0",
+		"network_udp_events_total{event=receive_buffer_errors,protocol=udp} 3",
+		"network_udp_events_total{event=send_buffer_errors,protocol=udp6} 0",
+		"network_udp_events_total{event=send_buffer_errors,protocol=udp} 0",
+	}
+	if got := gatherSeries(t, NetworkTCPEvents, NetworkUDPEvents); !reflect.DeepEqual(got, want) {
+		t.Errorf("series:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// IPv6 отключён, поле пропало, отрицательное значение не публикуется
+	setProtocolCounters(map[string]map[string]int64{
+		"Tcp": {"ActiveOpens": 48300, "OutRsts": -1},
+	}, nil, nil)
+	want = []string{
+		"network_tcp_events_total{event=active_opens} 48300",
+	}
+	if got := gatherSeries(t, NetworkTCPEvents, NetworkUDPEvents); !reflect.DeepEqual(got, want) {
+		t.Errorf("after reload:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,291 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bufio"
+	"encoding/hex"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resolvConfPath         = "/etc/resolv.conf"
+	resolvedUpstreamPath   = "/run/systemd/resolve/resolv.conf"
+	systemdResolvedStubIP  = "127.0.0.53"
+	networkConfigRefreshIn = time.Minute
+)
+
+// Флаги маршрутов IPv6 (include/uapi/linux/ipv6_route.h, route.h).
+const (
+	rtfCache   = 0x01000000
+	rtfAnycast = 0x00100000
+	rtfLocal   = 0x80000000
+)
+
+type routeEntry struct {
+	Iface   string
+	Family  string
+	Default bool
+	Gateway string
+}
+
+// networkConfigSeries — серии сетевой конфигурации. Удалённые адреса,
+// маршруты и резолверы пропадают при Flush следующего обновления.
+type networkConfigSeries struct {
+	address  *gaugeSeries
+	gateway  *gaugeSeries
+	routes   *gaugeSeries
+	resolver *gaugeSeries
+}
+
+// RecordNetworkConfig периодически публикует адреса интерфейсов, маршруты и
+// DNS-резолверы.
+func RecordNetworkConfig() {
+	go func() {
+		ticker := time.NewTicker(networkConfigRefreshIn)
+		defer ticker.Stop()
+
+		series := &networkConfigSeries{
+			address:  newGaugeSeries(NetworkAddressInfo),
+			gateway:  newGaugeSeries(NetworkDefaultGateway),
+			routes:   newGaugeSeries(NetworkRoutes),
+			resolver: newGaugeSeries(NetworkDNSResolverInfo),
+		}
+
+		for {
+			recordInterfaceAddresses(series.address)
+			recordRoutes(series)
+			recordDNSResolvers(series.resolver, resolvConfPath, resolvedUpstreamPath)
+
+			<-ticker.C
+		}
+	}()
+}
+
+func recordInterfaceAddresses(series *gaugeSeries) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		log.Printf("failed to list network interfaces: %v", err)
+		return
+	}
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+
+			prefix, _ := ipNet.Mask.Size()
+			series.Set(prometheus.Labels{
+				"device":  iface.Name,
+				"address": ipNet.IP.String(),
+				"family":  ipFamily(ipNet.IP),
+				"prefix":  strconv.Itoa(prefix),
+				"scope":   ipScope(ipNet.IP),
+			}, 1)
+		}
+	}
+	series.Flush()
+}
+
+func recordRoutes(series *networkConfigSeries) {
+	var routes []routeEntry
+
+	if data, err := os.ReadFile("/proc/net/route"); err == nil {
+		routes = append(routes, parseIPv4Routes(string(data))...)
+	} else {
+		log.Printf("failed to read IPv4 routes: %v", err)
+	}
+
+	if data, err := os.ReadFile("/proc/net/ipv6_route"); err == nil {
+		routes = append(routes, parseIPv6Routes(string(data))...)
+	}
+
+	counts := make(map[[2]string]int)
+	for _, route := range routes {
+		counts[[2]string{route.Iface, route.Family}]++
+
+		if route.Default && route.Gateway != "" {
+			series.gateway.Set(prometheus.Labels{
+				"device":  route.Iface,
+				"gateway": route.Gateway,
+				"family":  route.Family,
+			}, 1)
+		}
+	}
+
+	for key, count := range counts {
+		series.routes.Set(prometheus.Labels{
+			"device": key[0],
+			"family": key[1],
+		}, float64(count))
+	}
+
+	series.gateway.Flush()
+	series.routes.Flush()
+}
+
+func recordDNSResolvers(series *gaugeSeries, path, upstreamPath string) {
+	resolvers := readResolvConf(path)
+	for _, address := range resolvers {
+		series.Set(prometheus.Labels{"address": address, "source": path}, 1)
+	}
+
+	// при systemd-resolved в resolv.conf указан только локальный stub,
+	// реальные серверы перечислены в отдельном файле
+	if len(resolvers) == 1 && resolvers[0] == systemdResolvedStubIP {
+		for _, address := range readResolvConf(upstreamPath) {
+			series.Set(prometheus.Labels{"address": address, "source": upstreamPath}, 1)
+		}
+	}
+	series.Flush()
+}
+
+func readResolvConf(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var resolvers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			resolvers = append(resolvers, fields[1])
+		}
+	}
+	return resolvers
+}
+
+// parseIPv4Routes разбирает /proc/net/route. Адреса записаны в hex в порядке
+// байт хоста (little-endian).
+func parseIPv4Routes(data string) []routeEntry {
+	var routes []routeEntry
+
+	for i, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 8 {
+			continue
+		}
+
+		iface := fields[0]
+		if iface == "lo" {
+			continue
+		}
+
+		route := routeEntry{
+			Iface:   iface,
+			Family:  "ipv4",
+			Default: fields[1] == "00000000" && fields[7] == "00000000",
+		}
+
+		if gateway := parseHexIPv4(fields[2]); gateway != nil && !gateway.IsUnspecified() {
+			route.Gateway = gateway.String()
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+// parseIPv6Routes разбирает /proc/net/ipv6_route: dst, dst_len, src, src_len,
+// next_hop, metric, refcnt, use, flags, iface. Файл содержит маршруты всех
+// таблиц; маршруты таблицы local (собственные адреса хоста, anycast) и
+// закешированные маршруты пропускаются, чтобы счёт совпадал с IPv4.
+func parseIPv6Routes(data string) []routeEntry {
+	var routes []routeEntry
+
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+
+		iface := fields[9]
+		if iface == "lo" {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(fields[8], 16, 32)
+		if err != nil || flags&(rtfLocal|rtfAnycast|rtfCache) != 0 {
+			continue
+		}
+
+		dst := parseHexIPv6(fields[0])
+		if dst == nil {
+			continue
+		}
+		// multicast и link-local маршруты создаются ядром автоматически
+		if dst.IsMulticast() || dst.IsLinkLocalUnicast() {
+			continue
+		}
+
+		route := routeEntry{
+			Iface:   iface,
+			Family:  "ipv6",
+			Default: dst.IsUnspecified() && fields[1] == "00",
+		}
+
+		if gateway := parseHexIPv6(fields[4]); gateway != nil && !gateway.IsUnspecified() {
+			route.Gateway = gateway.String()
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes
+}
+
+func parseHexIPv4(value string) net.IP {
+	raw, err := hex.DecodeString(value)
+	if err != nil || len(raw) != net.IPv4len {
+		return nil
+	}
+	return net.IPv4(raw[3], raw[2], raw[1], raw[0])
+}
+
+func parseHexIPv6(value string) net.IP {
+	raw, err := hex.DecodeString(value)
+	if err != nil || len(raw) != net.IPv6len {
+		return nil
+	}
+	return net.IP(raw)
+}
+
+func ipFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+func ipScope(ip net.IP) string {
+	switch {
+	case ip.IsLoopback():
+		return "host"
+	case ip.IsLinkLocalUnicast():
+		return "link"
+	default:
+		return "global"
+	}
+}
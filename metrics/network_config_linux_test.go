@@ -0,0 +1,107 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Файлы testdata/net/* — /proc/net/route и /proc/net/ipv6_route хоста со
+// шлюзом на eth0, docker0 и WireGuard-туннелем wg0, а также resolv.conf
+// systemd-resolved (stub и upstream) и статический resolv.conf.
+
+func readNetFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "net", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseIPv4Routes(t *testing.T) {
+	got := parseIPv4Routes(readNetFixture(t, "proc_net_route"))
+	want := []routeEntry{
+		{Iface: "eth0", Family: "ipv4", Default: true, Gateway: "192.168.2.1"},
+		{Iface: "eth0", Family: "ipv4"},
+		{Iface: "docker0", Family: "ipv4"},
+		{Iface: "wg0", Family: "ipv4"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIPv4Routes() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseIPv6Routes(t *testing.T) {
+	// маршруты таблицы local (адреса хоста, anycast), link-local, multicast
+	// и маршруты на lo не учитываются
+	got := parseIPv6Routes(readNetFixture(t, "proc_net_ipv6_route"))
+	want := []routeEntry{
+		{Iface: "eth0", Family: "ipv6", Default: true, Gateway: "fe80::1"},
+		{Iface: "eth0", Family: "ipv6"},
+		{Iface: "wg0", Family: "ipv6"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIPv6Routes() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseRoutesMalformed(t *testing.T) {
+	if got := parseIPv4Routes("Iface\tDestination\nbroken line\n"); got != nil {
+		t.Errorf("parseIPv4Routes(malformed) = %+v, want nil", got)
+	}
+	ipv6 := "zz 00 " + strings.Repeat("0", 32) + " 00 " + strings.Repeat("0", 32) + " 00000000 00000000 00000000 00000001 eth0\n"
+	if got := parseIPv6Routes(ipv6); got != nil {
+		t.Errorf("parseIPv6Routes(malformed) = %+v, want nil", got)
+	}
+}
+
+func TestReadResolvConf(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"resolv.conf", []string{"127.0.0.53"}},
+		{"resolved_resolv.conf", []string{"192.168.2.1", "2001:db8:1::1"}},
+		{"resolv_static.conf", []string{"10.0.0.2", "10.0.0.3"}},
+		{"missing", nil},
+	}
+
+	for _, tt := range tests {
+		if got := readResolvConf(filepath.Join("testdata", "net", tt.name)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("readResolvConf(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecordDNSResolvers(t *testing.T) {
+	NetworkDNSResolverInfo.Reset()
+	series := newGaugeSeries(NetworkDNSResolverInfo)
+	dir := filepath.Join("testdata", "net")
+
+	// за stub systemd-resolved публикуются и реальные серверы
+	recordDNSResolvers(series, filepath.Join(dir, "resolv.conf"), filepath.Join(dir, "resolved_resolv.conf"))
+	want := []string{
+		"network_dns_resolver_info{address=127.0.0.53,source=testdata/net/resolv.conf} 1",
+		"network_dns_resolver_info{address=192.168.2.1,source=testdata/net/resolved_resolv.conf} 1",
+		"network_dns_resolver_info{address=2001:db8:1::1,source=testdata/net/resolved_resolv.conf} 1",
+	}
+	if got := gatherSeries(t, NetworkDNSResolverInfo); !reflect.DeepEqual(got, want) {
+		t.Errorf("stub resolver:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// после смены конфигурации прежние резолверы удаляются
+	recordDNSResolvers(series, filepath.Join(dir, "resolv_static.conf"), filepath.Join(dir, "resolved_resolv.conf"))
+	want = []string{
+		"network_dns_resolver_info{address=10.0.0.2,source=testdata/net/resolv_static.conf} 1",
+		"network_dns_resolver_info{address=10.0.0.3,source=testdata/net/resolv_static.conf} 1",
+	}
+	if got := gatherSeries(t, NetworkDNSResolverInfo); !reflect.DeepEqual(got, want) {
+		t.Errorf("static resolver:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
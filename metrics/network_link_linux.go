@@ -0,0 +1,123 @@
+//go:build linux
+
+package metrics
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const netSysfsRoot = "/sys/class/net"
+
+type interfaceLinkState struct {
+	OperState      string
+	Carrier        int64
+	HasCarrier     bool
+	CarrierChanges uint64
+	HasChanges     bool
+	SpeedMbps      int64
+	HasSpeed       bool
+	Duplex         string
+	MTU            int64
+	HasMTU         bool
+}
+
+// readInterfaceLinkState читает состояние линка из /sys/class/net/<iface>.
+// Для интерфейсов без линка ядро возвращает EINVAL на carrier/speed/duplex,
+// такие значения пропускаются.
+func readInterfaceLinkState(root, iface string) interfaceLinkState {
+	dir := filepath.Join(root, iface)
+	state := interfaceLinkState{
+		OperState: readSysfsValue(filepath.Join(dir, "operstate")),
+		Duplex:    readSysfsValue(filepath.Join(dir, "duplex")),
+	}
+
+	if value, err := strconv.ParseInt(readSysfsValue(filepath.Join(dir, "carrier")), 10, 64); err == nil {
+		state.Carrier, state.HasCarrier = value, true
+	}
+	if value, err := strconv.ParseUint(readSysfsValue(filepath.Join(dir, "carrier_changes")), 10, 64); err == nil {
+		state.CarrierChanges, state.HasChanges = value, true
+	}
+	// -1 (SPEED_UNKNOWN) у виртуальных интерфейсов и при отсутствии линка
+	if value, err := strconv.ParseInt(readSysfsValue(filepath.Join(dir, "speed")), 10, 64); err == nil && value > 0 {
+		state.SpeedMbps, state.HasSpeed = value, true
+	}
+	if value, err := strconv.ParseInt(readSysfsValue(filepath.Join(dir, "mtu")), 10, 64); err == nil {
+		state.MTU, state.HasMTU = value, true
+	}
+
+	if state.OperState == "" {
+		state.OperState = "unknown"
+	}
+	if state.Duplex == "" {
+		state.Duplex = "unknown"
+	}
+
+	return state
+}
+
+// linkSeries — серии состояния линка, обновляемые за цикл сбора. Серии с
+// прежними state/duplex и серии удалённых интерфейсов удаляются при
+// Flush/Commit, а не перед сбором, поэтому scrape не видит пропусков.
+type linkSeries struct {
+	operState      *gaugeSeries
+	duplex         *gaugeSeries
+	carrier        *gaugeSeries
+	speed          *gaugeSeries
+	mtu            *gaugeSeries
+	carrierChanges *constCounterBatch
+}
+
+func newLinkSeries() *linkSeries {
+	return &linkSeries{
+		operState:      newGaugeSeries(NetworkOperState),
+		duplex:         newGaugeSeries(NetworkDuplex),
+		carrier:        newGaugeSeries(NetworkCarrier),
+		speed:          newGaugeSeries(NetworkSpeed),
+		mtu:            newGaugeSeries(NetworkMTU),
+		carrierChanges: NetworkCarrierChanges.Batch(),
+	}
+}
+
+// Flush завершает цикл сбора.
+func (s *linkSeries) Flush() {
+	for _, series := range []*gaugeSeries{s.operState, s.duplex, s.carrier, s.speed, s.mtu} {
+		series.Flush()
+	}
+	s.carrierChanges.Commit()
+	s.carrierChanges = NetworkCarrierChanges.Batch()
+}
+
+func recordInterfaceLink(series *linkSeries, iface string) {
+	recordLinkState(series, iface, readInterfaceLinkState(netSysfsRoot, iface))
+}
+
+func recordLinkState(series *linkSeries, iface string, state interfaceLinkState) {
+	labels := prometheus.Labels{"device": iface}
+
+	series.operState.Set(prometheus.Labels{"device": iface, "state": state.OperState}, 1)
+	series.duplex.Set(prometheus.Labels{"device": iface, "duplex": state.Duplex}, 1)
+
+	if state.HasCarrier {
+		series.carrier.Set(labels, float64(state.Carrier))
+	} else {
+		// у выключенного интерфейса carrier не читается (EINVAL): линка нет
+		series.carrier.Set(labels, 0)
+	}
+
+	if state.HasSpeed {
+		series.speed.Set(labels, float64(state.SpeedMbps))
+	}
+
+	if state.HasMTU {
+		series.mtu.Set(labels, float64(state.MTU))
+	}
+
+	// carrier_changes ведёт ядро с момента создания интерфейса, публикуется
+	// абсолютное значение, чтобы не терять переключения до запуска агента
+	if state.HasChanges {
+		series.carrierChanges.Set(labels, float64(state.CarrierChanges))
+	}
+}
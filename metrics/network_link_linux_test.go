@@ -0,0 +1,117 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeSysfsFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadInterfaceLinkState(t *testing.T) {
+	root := t.TempDir()
+	writeSysfsFiles(t, filepath.Join(root, "eth0"), map[string]string{
+		"operstate":       "up",
+		"duplex":          "full",
+		"carrier":         "1",
+		"carrier_changes": "7",
+		"speed":           "1000",
+		"mtu":             "9000",
+	})
+	// выключенный интерфейс: carrier, speed и duplex не читаются (EINVAL)
+	writeSysfsFiles(t, filepath.Join(root, "eth1"), map[string]string{
+		"operstate":       "down",
+		"carrier_changes": "2",
+		"speed":           "-1",
+		"mtu":             "1500",
+	})
+
+	tests := []struct {
+		iface string
+		want  interfaceLinkState
+	}{
+		{"eth0", interfaceLinkState{
+			OperState: "up", Carrier: 1, HasCarrier: true, CarrierChanges: 7, HasChanges: true,
+			SpeedMbps: 1000, HasSpeed: true, Duplex: "full", MTU: 9000, HasMTU: true,
+		}},
+		{"eth1", interfaceLinkState{
+			OperState: "down", CarrierChanges: 2, HasChanges: true, Duplex: "unknown", MTU: 1500, HasMTU: true,
+		}},
+		{"missing", interfaceLinkState{OperState: "unknown", Duplex: "unknown"}},
+	}
+
+	for _, tt := range tests {
+		if got := readInterfaceLinkState(root, tt.iface); got != tt.want {
+			t.Errorf("%s: readInterfaceLinkState() = %+v, want %+v", tt.iface, got, tt.want)
+		}
+	}
+}
+
+func TestRecordLinkState(t *testing.T) {
+	NetworkOperState.Reset()
+	NetworkDuplex.Reset()
+	NetworkCarrier.Reset()
+	NetworkSpeed.Reset()
+	NetworkMTU.Reset()
+	NetworkCarrierChanges.Reset()
+
+	gather := func() []string {
+		return gatherSeries(t, NetworkOperState, NetworkDuplex, NetworkCarrier, NetworkSpeed, NetworkMTU, NetworkCarrierChanges)
+	}
+
+	series := newLinkSeries()
+	// переключения до запуска агента учитываются сразу
+	recordLinkState(series, "eth0", interfaceLinkState{
+		OperState: "up", Carrier: 1, HasCarrier: true, CarrierChanges: 7, HasChanges: true,
+		SpeedMbps: 1000, HasSpeed: true, Duplex: "full", MTU: 1500, HasMTU: true,
+	})
+	recordLinkState(series, "eth1", interfaceLinkState{OperState: "up", Duplex: "full", HasCarrier: true, Carrier: 1})
+	series.Flush()
+
+	want := []string{
+		"network_carrier_changes_total{device=eth0} 7",
+		"network_carrier{device=eth0} 1",
+		"network_carrier{device=eth1} 1",
+		"network_duplex{device=eth0,duplex=full} 1",
+		"network_duplex{device=eth1,duplex=full} 1",
+		"network_mtu_bytes{device=eth0} 1500",
+		"network_operstate{device=eth0,state=up} 1",
+		"network_operstate{device=eth1,state=up} 1",
+		"network_speed_mbps{device=eth0} 1000",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("first cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// eth0 потерял линк, eth1 удалён: прежние state/duplex и серии eth1 удаляются
+	recordLinkState(series, "eth0", interfaceLinkState{
+		OperState: "down", CarrierChanges: 8, HasChanges: true, Duplex: "unknown", MTU: 1500, HasMTU: true,
+	})
+	series.Flush()
+
+	want = []string{
+		"network_carrier_changes_total{device=eth0} 8",
+		"network_carrier{device=eth0} 0",
+		"network_duplex{device=eth0,duplex=unknown} 1",
+		"network_mtu_bytes{device=eth0} 1500",
+		"network_operstate{device=eth0,state=down} 1",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("second cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
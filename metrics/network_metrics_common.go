@@ -2,13 +2,27 @@ package metrics
 
 import "github.com/prometheus/client_golang/prometheus"
 
+// Сетевые метрики адресуются по лейблу device — системному имени интерфейса
+// (eth0, enp3s0, "Ethernet 2"), которое уникально в пределах хоста.
+// «Дружественное» имя (модель адаптера) носит справочный характер и есть только
+// у network_interface_info: у двух одинаковых сетевых карт оно совпадает, а его
+// смена (например, после обновления кеша lspci) не должна пересоздавать серии
+// трафика и сбрасывать счётчики. Остальные метрики присоединяются к info по device.
 var (
+	NetworkInterfaceInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_interface_info",
+			Help: "Network interface information: kernel name, friendly name, model, MAC address, PCI slot, driver",
+		},
+		[]string{"device", "interface", "model", "mac", "pci_slot", "driver"},
+	)
+
 	NetworkStatus = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "network_status",
 			Help: "Network status on system (1 = up, 0 = down)",
 		},
-		[]string{"interface"},
+		[]string{"device"},
 	)
 
 	NetworkRxBytesPerSecond = prometheus.NewGaugeVec(
@@ -16,7 +30,7 @@ var (
 			Name: "network_rx_bytes_per_second",
 			Help: "Incoming network traffic in bytes per second",
 		},
-		[]string{"interface"},
+		[]string{"device"},
 	)
 
 	NetworkTxBytesPerSecond = prometheus.NewGaugeVec(
@@ -24,7 +38,7 @@ var (
 			Name: "network_tx_bytes_per_second",
 			Help: "Outgoing network traffic in bytes per second",
 		},
-		[]string{"interface"},
+		[]string{"device"},
 	)
 
 	NetworkErrors = prometheus.NewCounterVec(
@@ -32,7 +46,7 @@ var (
 			Name: "network_errors",
 			Help: "Number of network errors",
 		},
-		[]string{"interface"},
+		[]string{"device"},
 	)
 
 	NetworkDroppedPackets = prometheus.NewCounterVec(
@@ -40,6 +54,85 @@ var (
 			Name: "network_dropped_packets",
 			Help: "Number of network dropped packets",
 		},
-		[]string{"interface"},
+		[]string{"device"},
+	)
+
+	NetworkOperState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_operstate",
+			Help: "Operational state of network interface (RFC 2863: up, down, dormant, lowerlayerdown...), 1 for current state",
+		},
+		[]string{"device", "state"},
+	)
+
+	NetworkCarrier = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_carrier",
+			Help: "Physical link carrier of network interface (1 = link detected, 0 = no link)",
+		},
+		[]string{"device"},
+	)
+
+	NetworkSpeed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_speed_mbps",
+			Help: "Negotiated link speed of network interface in Mbit/s",
+		},
+		[]string{"device"},
+	)
+
+	NetworkDuplex = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_duplex",
+			Help: "Negotiated duplex mode of network interface (full/half/unknown), 1 for current mode",
+		},
+		[]string{"device", "duplex"},
+	)
+
+	NetworkMTU = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_mtu_bytes",
+			Help: "MTU of network interface in bytes",
+		},
+		[]string{"device"},
+	)
+
+	NetworkAddressInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_address_info",
+			Help: "IP address assigned to network interface",
+		},
+		[]string{"device", "address", "family", "prefix", "scope"},
+	)
+
+	NetworkDefaultGateway = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_default_gateway_info",
+			Help: "Default gateway configured on network interface",
+		},
+		[]string{"device", "gateway", "family"},
 	)
+
+	NetworkRoutes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_routes",
+			Help: "Number of routes per interface and address family (IPv4: main table; IPv6: all tables except local)",
+		},
+		[]string{"device", "family"},
+	)
+
+	NetworkDNSResolverInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "network_dns_resolver_info",
+			Help: "Configured DNS resolver",
+		},
+		[]string{"address", "source"},
+	)
+)
+
+// carrier_changes ведёт ядро, агент публикует его абсолютное значение.
+var NetworkCarrierChanges = newConstCounterVec(
+	"network_carrier_changes_total",
+	"Number of link carrier changes (flaps) of network interface since it was created",
+	[]string{"device"},
 )
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -17,17 +18,38 @@ import (
 )
 
 var (
-	ifaceNameCache       map[string]string
-	ifaceNameCacheExpiry time.Time
-	ifaceNameMu          sync.Mutex
+	ifaceDetailsCache       map[string]interfaceDetails
+	ifaceDetailsCacheExpiry time.Time
+	ifaceDetailsRefreshedAt time.Time
+	ifaceDetailsMu          sync.Mutex
 )
 
+// ifaceDetailsMissRefresh ограничивает внеплановое обновление кеша, когда
+// запрошен интерфейс, которого в кеше ещё нет.
+const ifaceDetailsMissRefresh = 30 * time.Second
+
+var pciSlotPattern = regexp.MustCompile(`^[0-9a-f]{4}:[0-9a-f]{2}:[0-9a-f]{2}\.[0-7]$`)
+
+type interfaceDetails struct {
+	Model   string
+	MAC     string
+	PCISlot string
+	Driver  string
+}
+
 func RecordNetworkMetrics() {
 	go func() {
 		ticker := time.NewTicker(5 * time.Second)
 		defer ticker.Stop()
 
-		prevStats := make(map[string]trackedInterfaceStat)
+		counters := newCounterTracker()
+		infoSeries := newGaugeSeries(NetworkInterfaceInfo)
+		statusSeries := newGaugeSeries(NetworkStatus)
+		rxSeries := newGaugeSeries(NetworkRxBytesPerSecond)
+		txSeries := newGaugeSeries(NetworkTxBytesPerSecond)
+		links := newLinkSeries()
+		// интерфейсы, для которых опубликованы счётчики ошибок и отброшенных пакетов
+		published := make(map[string]bool)
 
 		for {
 			interfaces, err := net.Interfaces()
@@ -37,7 +59,7 @@ func RecordNetworkMetrics() {
 				continue
 			}
 
-			displayNames := make(map[string]string, len(interfaces))
+			present := make(map[string]bool, len(interfaces))
 			for _, iface := range interfaces {
 				if iface.Name == "lo" {
 					continue
@@ -47,8 +69,8 @@ func RecordNetworkMetrics() {
 					continue
 				}
 
-				display := friendlyInterfaceName(iface.Name)
-				displayNames[iface.Name] = display
+				present[iface.Name] = true
+				recordInterfaceInfo(infoSeries, iface.Name)
 
 				status := 0.0
 				for _, flag := range iface.Flags {
@@ -58,7 +80,8 @@ func RecordNetworkMetrics() {
 					}
 				}
 
-				NetworkStatus.With(prometheus.Labels{"interface": display}).Set(status)
+				statusSeries.Set(prometheus.Labels{"device": iface.Name}, status)
+				recordInterfaceLink(links, iface.Name)
 			}
 
 			stats, err := net.IOCounters(true)
@@ -73,112 +96,152 @@ func RecordNetworkMetrics() {
 					continue
 				}
 
-				display := displayNames[stat.Name]
-				if display == "" {
-					display = friendlyInterfaceName(stat.Name)
-					displayNames[stat.Name] = display
-					NetworkStatus.With(prometheus.Labels{"interface": display}).Set(0)
+				if !present[stat.Name] {
+					present[stat.Name] = true
+					recordInterfaceInfo(infoSeries, stat.Name)
+					statusSeries.Set(prometheus.Labels{"device": stat.Name}, 0)
 				}
 
-				labels := prometheus.Labels{"interface": display}
-				if prev, ok := prevStats[stat.Name]; ok {
-					elapsed := time.Since(prev.Timestamp).Seconds()
-					if elapsed <= 0 {
-						elapsed = 5
-					}
-
-					rxRate := float64(stat.BytesRecv-prev.Counter.BytesRecv) / elapsed
-					txRate := float64(stat.BytesSent-prev.Counter.BytesSent) / elapsed
-
-					if rxRate < 0 {
-						rxRate = 0
-					}
-					if txRate < 0 {
-						txRate = 0
-					}
-
-					NetworkRxBytesPerSecond.With(labels).Set(rxRate)
-					NetworkTxBytesPerSecond.With(labels).Set(txRate)
-
-					errDelta := (stat.Errin + stat.Errout) - (prev.Counter.Errin + prev.Counter.Errout)
-					dropDelta := (stat.Dropin + stat.Dropout) - (prev.Counter.Dropin + prev.Counter.Dropout)
-					if errDelta < 0 {
-						errDelta = 0
-					}
-					if dropDelta < 0 {
-						dropDelta = 0
-					}
+				labels := prometheus.Labels{"device": stat.Name}
+				now := time.Now()
+				// ifindex меняется при пересоздании интерфейса с тем же именем
+				identity := readSysfsValue(filepath.Join(netSysfsRoot, stat.Name, "ifindex"))
+
+				// /proc/net/dev выводит 64-битные rtnl_link_stats64
+				rx := counters.Observe(stat.Name+"|rx", identity, stat.BytesRecv, counter64, now)
+				tx := counters.Observe(stat.Name+"|tx", identity, stat.BytesSent, counter64, now)
+				rxSeries.Set(labels, rx.rate())
+				txSeries.Set(labels, tx.rate())
+
+				errDelta := counters.Observe(stat.Name+"|errin", identity, stat.Errin, counter64, now).Delta +
+					counters.Observe(stat.Name+"|errout", identity, stat.Errout, counter64, now).Delta
+				dropDelta := counters.Observe(stat.Name+"|dropin", identity, stat.Dropin, counter64, now).Delta +
+					counters.Observe(stat.Name+"|dropout", identity, stat.Dropout, counter64, now).Delta
+				NetworkErrors.With(labels).Add(float64(errDelta))
+				NetworkDroppedPackets.With(labels).Add(float64(dropDelta))
+			}
 
-					NetworkErrors.With(labels).Add(float64(errDelta))
-					NetworkDroppedPackets.With(labels).Add(float64(dropDelta))
-				} else {
-					NetworkRxBytesPerSecond.With(labels).Set(0)
-					NetworkTxBytesPerSecond.With(labels).Set(0)
-					NetworkErrors.With(labels).Add(0)
-					NetworkDroppedPackets.With(labels).Add(0)
+			// серии удалённых интерфейсов не должны оставаться с замороженными значениями
+			for _, series := range []*gaugeSeries{infoSeries, statusSeries, rxSeries, txSeries} {
+				series.Flush()
+			}
+			links.Flush()
+			for device := range published {
+				if !present[device] {
+					deleteNetworkDeviceSeries(device)
 				}
-
-				prevStats[stat.Name] = trackedInterfaceStat{Counter: stat, Timestamp: time.Now()}
 			}
+			published = present
 
+			counters.Prune(time.Now().Add(-time.Minute))
 			<-ticker.C
 		}
 	}()
 }
 
-type trackedInterfaceStat struct {
-	Counter   net.IOCountersStat
-	Timestamp time.Time
+// deleteNetworkDeviceSeries удаляет счётчики ошибок и отброшенных пакетов
+// удалённого интерфейса device; остальные серии удаляются при Flush.
+func deleteNetworkDeviceSeries(device string) {
+	labels := prometheus.Labels{"device": device}
+	NetworkErrors.DeletePartialMatch(labels)
+	NetworkDroppedPackets.DeletePartialMatch(labels)
+}
+
+// recordInterfaceInfo публикует network_interface_info с дружественным именем
+// интерфейса в лейбле interface.
+func recordInterfaceInfo(series *gaugeSeries, iface string) {
+	details := interfaceDetailsFor(iface)
+
+	series.Set(prometheus.Labels{
+		"device":    iface,
+		"interface": interfaceDisplayName(iface, details),
+		"model":     orUnknown(details.Model),
+		"mac":       orUnknown(details.MAC),
+		"pci_slot":  orUnknown(details.PCISlot),
+		"driver":    orUnknown(details.Driver),
+	}, 1)
 }
 
-func friendlyInterfaceName(iface string) string {
-	ifaceNameMu.Lock()
-	defer ifaceNameMu.Unlock()
+// interfaceDisplayName возвращает дружественное имя интерфейса: модель адаптера
+// или системное имя для виртуальных интерфейсов.
+func interfaceDisplayName(iface string, details interfaceDetails) string {
+	if details.Model != "" {
+		return details.Model
+	}
+	return iface
+}
 
-	if time.Now().After(ifaceNameCacheExpiry) {
-		ifaceNameCache = loadInterfaceNames()
-		ifaceNameCacheExpiry = time.Now().Add(5 * time.Minute)
+func interfaceDetailsFor(iface string) interfaceDetails {
+	ifaceDetailsMu.Lock()
+	defer ifaceDetailsMu.Unlock()
+
+	_, known := ifaceDetailsCache[iface]
+	// новый интерфейс сразу получает модель, а не системное имя до планового
+	// обновления кеша
+	if time.Now().After(ifaceDetailsCacheExpiry) || (!known && time.Since(ifaceDetailsRefreshedAt) > ifaceDetailsMissRefresh) {
+		ifaceDetailsCache = loadInterfaceDetails()
+		ifaceDetailsRefreshedAt = time.Now()
+		ifaceDetailsCacheExpiry = ifaceDetailsRefreshedAt.Add(5 * time.Minute)
 	}
 
-	if name, ok := ifaceNameCache[iface]; ok && name != "" {
-		return name
+	if details, ok := ifaceDetailsCache[iface]; ok {
+		return details
 	}
 
-	return iface
+	// интерфейс мог появиться после обновления кеша
+	return interfaceDetails{MAC: readSysfsValue(filepath.Join("/sys/class/net", iface, "address"))}
 }
 
-func loadInterfaceNames() map[string]string {
-	names := make(map[string]string)
+func loadInterfaceDetails() map[string]interfaceDetails {
+	details := make(map[string]interfaceDetails)
 
 	pciNames := loadPCINetworkNames()
 
 	entries, err := os.ReadDir("/sys/class/net")
 	if err != nil {
-		return names
+		return details
 	}
 
 	for _, entry := range entries {
 		iface := entry.Name()
+		info := interfaceDetails{
+			MAC: readSysfsValue(filepath.Join("/sys/class/net", iface, "address")),
+		}
+
 		devicePath := filepath.Join("/sys/class/net", iface, "device")
 		resolved, err := filepath.EvalSymlinks(devicePath)
 		if err != nil {
+			// виртуальные интерфейсы (bridge, veth, tun) не имеют устройства
+			details[iface] = info
 			continue
 		}
 
+		if driver, err := filepath.EvalSymlinks(filepath.Join(devicePath, "driver")); err == nil {
+			info.Driver = filepath.Base(driver)
+		}
+
 		busID := filepath.Base(resolved)
-		if label, ok := pciNames[busID]; ok {
-			names[iface] = label
-			continue
+		if pciSlotPattern.MatchString(busID) {
+			info.PCISlot = busID
+		} else if parent := filepath.Base(filepath.Dir(resolved)); pciSlotPattern.MatchString(parent) {
+			// virtio-net и подобные: устройство висит на PCI-функции уровнем выше
+			info.PCISlot = parent
 		}
 
-		vendor := strings.TrimPrefix(readSysfsValue(filepath.Join(resolved, "vendor")), "0x")
-		device := strings.TrimPrefix(readSysfsValue(filepath.Join(resolved, "device")), "0x")
-		if vendor != "" && device != "" {
-			names[iface] = fmt.Sprintf("PCI %s:%s", vendor, device)
+		if label, ok := pciNames[busID]; ok {
+			info.Model = label
+		} else {
+			vendor := strings.TrimPrefix(readSysfsValue(filepath.Join(resolved, "vendor")), "0x")
+			device := strings.TrimPrefix(readSysfsValue(filepath.Join(resolved, "device")), "0x")
+			if vendor != "" && device != "" {
+				info.Model = fmt.Sprintf("PCI %s:%s", vendor, device)
+			}
 		}
+
+		details[iface] = info
 	}
 
-	return names
+	return details
 }
 
 func loadPCINetworkNames() map[string]string {
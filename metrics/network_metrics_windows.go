@@ -21,6 +21,7 @@ type Win32_NetworkAdapter struct {
 	PNPDeviceID     string
 	Description     string
 	NetConnectionID string
+	ServiceName     string
 }
 
 // GetPhysicalNetworkAdapters retrieves information about physical network adapters in the system
@@ -32,7 +33,7 @@ func GetPhysicalNetworkAdapters() ([]Win32_NetworkAdapter, error) {
 	var adapters []Win32_NetworkAdapter
 	err := wmi.Query(
 		`SELECT Name, MACAddress, Manufacturer, NetEnabled, PNPDeviceID, 
-		Description, NetConnectionID FROM Win32_NetworkAdapter 
+		Description, NetConnectionID, ServiceName FROM Win32_NetworkAdapter 
 		WHERE MACAddress IS NOT NULL AND PhysicalAdapter = TRUE`,
 		&adapters,
 	)
@@ -69,73 +70,83 @@ func GetNetworkIOStats() ([]net.IOCountersStat, error) {
 	return stats, nil
 }
 
+// networkSeries holds the gauge series updated every collection cycle. Series
+// of removed adapters are deleted on Flush instead of resetting the vectors,
+// so a scrape never sees them empty.
+type networkSeries struct {
+	info   *gaugeSeries
+	status *gaugeSeries
+	rx     *gaugeSeries
+	tx     *gaugeSeries
+}
+
 // RecordNetworkAdapterStatus updates the Prometheus metric for network status
 // based on the enabled state of each network adapter. It sets the metric to 1.0
 // if the adapter is enabled and 0.0 if it is disabled. The metric is labeled
-// with the name of the network interface.
+// with the connection name (device). It also publishes network_interface_info
+// with the adapter name (interface), MAC address and driver service. WMI
+// exposes neither a model distinct from the adapter name nor the PCI
+// bus/device/function, so model and pci_slot are left unknown.
 
-func RecordNetworkAdapterStatus(adapters []Win32_NetworkAdapter) {
+func RecordNetworkAdapterStatus(series *networkSeries, adapters []Win32_NetworkAdapter) {
 	for _, adapter := range adapters {
 		status := 0.0
 		if adapter.NetEnabled {
 			status = 1.0
 		}
-		NetworkStatus.With(prometheus.Labels{
+		series.status.Set(prometheus.Labels{"device": adapter.NetConnectionID}, status)
+
+		series.info.Set(prometheus.Labels{
+			"device":    adapter.NetConnectionID,
 			"interface": adapter.Name,
-		}).Set(status)
+			"model":     orUnknown(""),
+			"mac":       orUnknown(strings.ToLower(adapter.MACAddress)),
+			"pci_slot":  orUnknown(""),
+			"driver":    orUnknown(adapter.ServiceName),
+		}, 1)
 	}
 }
 
-// RecordNetworkTraffic records network traffic metrics based on the given
-// previous and current network statistics. It records the following metrics
-// for each network interface:
+// RecordNetworkTraffic records network traffic metrics for each physical
+// network interface. Counter deltas are computed by the shared counter
+// tracker, which handles counter resets, 32/64-bit wraparound and adapter
+// re-creation. It records the following metrics:
 //
 // * NetworkRxBytesPerSecond: The number of bytes received per second
 // * NetworkTxBytesPerSecond: The number of bytes sent per second
 // * NetworkErrors: The total number of errors (inbound and outbound)
 // * NetworkDroppedPackets: The total number of dropped packets (inbound and outbound)
-//
-// It returns a new map of current network statistics for the next call.
-func RecordNetworkTraffic(prevStats map[string]net.IOCountersStat, currentStats []net.IOCountersStat, adapterMap map[string]string) map[string]net.IOCountersStat {
-	newStats := make(map[string]net.IOCountersStat)
+func RecordNetworkTraffic(series *networkSeries, counters *counterTracker, currentStats []net.IOCountersStat, adapterMap map[string]string) {
+	now := time.Now()
 
 	for _, stat := range currentStats {
-		if name, ok := adapterMap[stat.Name]; ok {
-			if prev, exists := prevStats[stat.Name]; exists {
-				duration := 5.0
-				rxRate := float64(stat.BytesRecv-prev.BytesRecv) / duration
-				txRate := float64(stat.BytesSent-prev.BytesSent) / duration
-
-				NetworkRxBytesPerSecond.With(prometheus.Labels{"interface": name}).Set(rxRate)
-				NetworkTxBytesPerSecond.With(prometheus.Labels{"interface": name}).Set(txRate)
-
-				// корректные дельты без underflow для uint64
-				currErr := stat.Errin + stat.Errout
-				prevErr := prev.Errin + prev.Errout
-				var errDelta uint64
-				if currErr >= prevErr {
-					errDelta = currErr - prevErr
-				} else {
-					errDelta = 0
-				}
-
-				currDrop := stat.Dropin + stat.Dropout
-				prevDrop := prev.Dropin + prev.Dropout
-				var dropDelta uint64
-				if currDrop >= prevDrop {
-					dropDelta = currDrop - prevDrop
-				} else {
-					dropDelta = 0
-				}
+		name, ok := adapterMap[stat.Name]
+		if !ok {
+			continue
+		}
 
-				NetworkErrors.With(prometheus.Labels{"interface": name}).Add(float64(errDelta))
-				NetworkDroppedPackets.With(prometheus.Labels{"interface": name}).Add(float64(dropDelta))
-			}
-			newStats[stat.Name] = stat
+		// имя адаптера меняется при замене сетевой карты под тем же подключением
+		identity := name
+		// GetIfEntry2 отдаёт 64-битные счётчики MIB_IF_ROW2
+		rx := counters.Observe(stat.Name+"|rx", identity, stat.BytesRecv, counter64, now)
+		tx := counters.Observe(stat.Name+"|tx", identity, stat.BytesSent, counter64, now)
+		errDelta := counters.Observe(stat.Name+"|errin", identity, stat.Errin, counter64, now).Delta +
+			counters.Observe(stat.Name+"|errout", identity, stat.Errout, counter64, now).Delta
+		dropDelta := counters.Observe(stat.Name+"|dropin", identity, stat.Dropin, counter64, now).Delta +
+			counters.Observe(stat.Name+"|dropout", identity, stat.Dropout, counter64, now).Delta
+
+		if !rx.Valid {
+			continue
 		}
+
+		labels := prometheus.Labels{"device": stat.Name}
+		series.rx.Set(labels, rx.rate())
+		series.tx.Set(labels, tx.rate())
+		NetworkErrors.With(labels).Add(float64(errDelta))
+		NetworkDroppedPackets.With(labels).Add(float64(dropDelta))
 	}
 
-	return newStats
+	counters.Prune(now.Add(-time.Minute))
 }
 
 // RecordNetworkMetrics runs in a separate goroutine and updates the network metrics
@@ -143,7 +154,15 @@ func RecordNetworkTraffic(prevStats map[string]net.IOCountersStat, currentStats
 // network traffic metrics.
 func RecordNetworkMetrics() {
 	go func() {
-		prevStats := make(map[string]net.IOCountersStat)
+		counters := newCounterTracker()
+		series := &networkSeries{
+			info:   newGaugeSeries(NetworkInterfaceInfo),
+			status: newGaugeSeries(NetworkStatus),
+			rx:     newGaugeSeries(NetworkRxBytesPerSecond),
+			tx:     newGaugeSeries(NetworkTxBytesPerSecond),
+		}
+		// подключения, для которых опубликованы счётчики ошибок
+		published := make(map[string]string)
 
 		for {
 			// Получение только физических сетевых адаптеров через WMI
@@ -161,7 +180,7 @@ func RecordNetworkMetrics() {
 			}
 
 			// Запись статуса адаптеров
-			RecordNetworkAdapterStatus(adapters)
+			RecordNetworkAdapterStatus(series, adapters)
 
 			// Получение статистики
 			ioStats, err := GetNetworkIOStats()
@@ -172,7 +191,19 @@ func RecordNetworkMetrics() {
 			}
 
 			// Запись метрик трафика
-			prevStats = RecordNetworkTraffic(prevStats, ioStats, adapterMap)
+			RecordNetworkTraffic(series, counters, ioStats, adapterMap)
+
+			for _, s := range []*gaugeSeries{series.info, series.status, series.rx, series.tx} {
+				s.Flush()
+			}
+			for device := range published {
+				if _, ok := adapterMap[device]; !ok {
+					labels := prometheus.Labels{"device": device}
+					NetworkErrors.DeletePartialMatch(labels)
+					NetworkDroppedPackets.DeletePartialMatch(labels)
+				}
+			}
+			published = adapterMap
 
 			time.Sleep(5 * time.Second)
 		}
@@ -0,0 +1,74 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	NvmeControllerInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvme_controller_info",
+			Help: "NVMe controller information: model, serial, firmware revision, transport",
+		},
+		[]string{"controller", "model", "serial", "firmware", "transport"},
+	)
+
+	NvmeNamespaceSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvme_namespace_size_bytes",
+			Help: "Size of NVMe namespace in bytes",
+		},
+		[]string{"controller", "namespace"},
+	)
+
+	NvmePCIeLinkSpeed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvme_pcie_link_speed_gts",
+			Help: "PCIe link speed of NVMe controller in GT/s (type = current/max)",
+		},
+		[]string{"controller", "type"},
+	)
+
+	NvmePCIeLinkWidth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvme_pcie_link_width",
+			Help: "PCIe link width of NVMe controller in lanes (type = current/max)",
+		},
+		[]string{"controller", "type"},
+	)
+
+	NvmeCriticalWarning = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvme_critical_warning",
+			Help: "NVMe critical warning bits from SMART log (1 = set, 0 = clear)",
+		},
+		[]string{"controller", "bit"},
+	)
+
+	NvmePercentageUsed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "nvme_percentage_used",
+			Help: "Vendor estimate of NVMe endurance used in percent",
+		},
+		[]string{"controller"},
+	)
+)
+
+// Счётчики SMART-лога ведёт контроллер, агент публикует их абсолютные значения.
+var (
+	NvmeDataWrittenBytes = newConstCounterVec(
+		"nvme_data_written_bytes_total",
+		"Total data written to NVMe device in bytes (data_units_written * 512000)",
+		[]string{"controller"},
+	)
+
+	NvmeThermalThrottleTransitions = newConstCounterVec(
+		"nvme_thermal_throttle_transitions_total",
+		"Number of times NVMe controller entered thermal management temperature state",
+		[]string{"controller", "sensor"},
+	)
+
+	NvmeThermalThrottleSeconds = newConstCounterVec(
+		"nvme_thermal_throttle_seconds_total",
+		"Total time NVMe controller spent in thermal management temperature state",
+		[]string{"controller", "sensor"},
+	)
+)
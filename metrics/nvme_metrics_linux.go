@@ -0,0 +1,331 @@
+//go:build linux
+
+package metrics
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const nvmeSysfsRoot = "/sys/class/nvme"
+
+// биты critical_warning из SMART / Health Information log (NVMe Base Spec, 5.14.1.2)
+var nvmeCriticalWarningBits = []string{
+	"available_spare",
+	"temperature",
+	"reliability",
+	"read_only",
+	"volatile_memory_backup",
+	"persistent_memory_read_only",
+}
+
+type nvmeController struct {
+	Name       string
+	Model      string
+	Serial     string
+	Firmware   string
+	Transport  string
+	PCIDir     string
+	Namespaces map[string]uint64
+}
+
+type nvmeSmartLog struct {
+	CriticalWarning    uint64
+	HasCriticalWarning bool
+	PercentageUsed     float64
+	HasPercentageUsed  bool
+	DataUnitsWritten   float64
+	HasDataUnits       bool
+	ThrottleCounts     map[string]float64
+	ThrottleSeconds    map[string]float64
+}
+
+// nvmeSeries — серии NVMe, обновляемые за цикл сбора. Серии исчезнувших
+// контроллеров и namespace удаляются при Flush/Commit.
+type nvmeSeries struct {
+	info            *gaugeSeries
+	namespaceSize   *gaugeSeries
+	linkSpeed       *gaugeSeries
+	linkWidth       *gaugeSeries
+	criticalWarning *gaugeSeries
+	percentageUsed  *gaugeSeries
+
+	dataWritten     *constCounterBatch
+	throttleCounts  *constCounterBatch
+	throttleSeconds *constCounterBatch
+}
+
+func RecordNvmeMetrics() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		series := nvmeSeries{
+			info:            newGaugeSeries(NvmeControllerInfo),
+			namespaceSize:   newGaugeSeries(NvmeNamespaceSize),
+			linkSpeed:       newGaugeSeries(NvmePCIeLinkSpeed),
+			linkWidth:       newGaugeSeries(NvmePCIeLinkWidth),
+			criticalWarning: newGaugeSeries(NvmeCriticalWarning),
+			percentageUsed:  newGaugeSeries(NvmePercentageUsed),
+		}
+
+		for {
+			recordNvmeControllers(&series, discoverNvmeControllers(nvmeSysfsRoot))
+			<-ticker.C
+		}
+	}()
+}
+
+func recordNvmeControllers(series *nvmeSeries, controllers []nvmeController) {
+	series.dataWritten = NvmeDataWrittenBytes.Batch()
+	series.throttleCounts = NvmeThermalThrottleTransitions.Batch()
+	series.throttleSeconds = NvmeThermalThrottleSeconds.Batch()
+
+	for _, ctrl := range controllers {
+		series.info.Set(prometheus.Labels{
+			"controller": ctrl.Name,
+			"model":      ctrl.Model,
+			"serial":     ctrl.Serial,
+			"firmware":   ctrl.Firmware,
+			"transport":  ctrl.Transport,
+		}, 1)
+
+		for ns, size := range ctrl.Namespaces {
+			series.namespaceSize.Set(prometheus.Labels{
+				"controller": ctrl.Name,
+				"namespace":  ns,
+			}, float64(size))
+		}
+
+		recordNvmePCIeLink(series, ctrl)
+
+		// SMART-лог опрашивается фоновым воркером здоровья дисков
+		if smartLog, ok := cachedNvmeSmartLog(ctrl.Name); ok {
+			recordNvmeSmartLog(series, ctrl.Name, smartLog)
+		}
+	}
+
+	series.info.Flush()
+	series.namespaceSize.Flush()
+	series.linkSpeed.Flush()
+	series.linkWidth.Flush()
+	series.criticalWarning.Flush()
+	series.percentageUsed.Flush()
+	series.dataWritten.Commit()
+	series.throttleCounts.Commit()
+	series.throttleSeconds.Commit()
+}
+
+func discoverNvmeControllers(root string) []nvmeController {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var controllers []nvmeController
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "nvme") {
+			continue
+		}
+
+		dir := filepath.Join(root, name)
+		ctrl := nvmeController{
+			Name:       name,
+			Model:      readSysfsValue(filepath.Join(dir, "model")),
+			Serial:     readSysfsValue(filepath.Join(dir, "serial")),
+			Firmware:   readSysfsValue(filepath.Join(dir, "firmware_rev")),
+			Transport:  readSysfsValue(filepath.Join(dir, "transport")),
+			Namespaces: make(map[string]uint64),
+		}
+
+		if ctrl.Model == "" {
+			ctrl.Model = "unknown"
+		}
+		if ctrl.Serial == "" {
+			ctrl.Serial = "unknown"
+		}
+		if ctrl.Firmware == "" {
+			ctrl.Firmware = "unknown"
+		}
+		if ctrl.Transport == "" {
+			ctrl.Transport = "unknown"
+		}
+
+		if resolved, err := filepath.EvalSymlinks(filepath.Join(dir, "device")); err == nil {
+			ctrl.PCIDir = resolved
+		}
+
+		nsEntries, err := os.ReadDir(dir)
+		if err == nil {
+			for _, ns := range nsEntries {
+				nsName := ns.Name()
+				// nvme0n1 или nvme0c0n1 при native multipath
+				if !strings.HasPrefix(nsName, "nvme") || !strings.Contains(strings.TrimPrefix(nsName, "nvme"), "n") {
+					continue
+				}
+
+				sectors := readSysfsValue(filepath.Join(dir, nsName, "size"))
+				if sectors == "" {
+					continue
+				}
+				if value, err := strconv.ParseUint(sectors, 10, 64); err == nil {
+					ctrl.Namespaces[nsName] = value * 512
+				}
+			}
+		}
+
+		controllers = append(controllers, ctrl)
+	}
+
+	return controllers
+}
+
+func recordNvmePCIeLink(series *nvmeSeries, ctrl nvmeController) {
+	if ctrl.PCIDir == "" {
+		return
+	}
+
+	for _, typ := range []string{"current", "max"} {
+		if speed, ok := parsePCIeLinkSpeed(readSysfsValue(filepath.Join(ctrl.PCIDir, typ+"_link_speed"))); ok {
+			series.linkSpeed.Set(prometheus.Labels{"controller": ctrl.Name, "type": typ}, speed)
+		}
+
+		width := readSysfsValue(filepath.Join(ctrl.PCIDir, typ+"_link_width"))
+		if value, err := strconv.ParseFloat(width, 64); err == nil {
+			series.linkWidth.Set(prometheus.Labels{"controller": ctrl.Name, "type": typ}, value)
+		}
+	}
+}
+
+// parsePCIeLinkSpeed разбирает значения вида "8.0 GT/s PCIe" или "16 GT/s".
+func parsePCIeLinkSpeed(value string) (float64, bool) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	speed, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false
+	}
+	return speed, true
+}
+
+func recordNvmeSmartLog(series *nvmeSeries, controller string, smartLog nvmeSmartLog) {
+	if smartLog.HasCriticalWarning {
+		for bit, name := range nvmeCriticalWarningBits {
+			value := 0.0
+			if smartLog.CriticalWarning&(1<<uint(bit)) != 0 {
+				value = 1
+			}
+			series.criticalWarning.Set(prometheus.Labels{"controller": controller, "bit": name}, value)
+		}
+	}
+
+	if smartLog.HasPercentageUsed {
+		series.percentageUsed.Set(prometheus.Labels{"controller": controller}, smartLog.PercentageUsed)
+	}
+
+	if smartLog.HasDataUnits {
+		// одна единица data_units = 1000 блоков по 512 байт
+		series.dataWritten.Set(prometheus.Labels{"controller": controller}, smartLog.DataUnitsWritten*512000)
+	}
+
+	for sensor, count := range smartLog.ThrottleCounts {
+		series.throttleCounts.Set(prometheus.Labels{"controller": controller, "sensor": sensor}, count)
+	}
+	for sensor, seconds := range smartLog.ThrottleSeconds {
+		series.throttleSeconds.Set(prometheus.Labels{"controller": controller, "sensor": sensor}, seconds)
+	}
+}
+
+func queryNvmeSmartLog(device string) (nvmeSmartLog, error) {
+	cmd := exec.Command("nvme", "smart-log", "-o", "json", device)
+	output, err := cmd.Output()
+	if err != nil {
+		return nvmeSmartLog{}, err
+	}
+
+	smartLog, err := parseNvmeSmartLog(output)
+	if err != nil {
+		log.Printf("failed to parse nvme smart-log for %s: %v", device, err)
+	}
+	return smartLog, err
+}
+
+func parseNvmeSmartLog(data []byte) (nvmeSmartLog, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nvmeSmartLog{}, err
+	}
+
+	smartLog := nvmeSmartLog{
+		ThrottleCounts:  make(map[string]float64),
+		ThrottleSeconds: make(map[string]float64),
+	}
+
+	if value, ok := nvmeJSONNumber(raw, "critical_warning"); ok {
+		smartLog.CriticalWarning = uint64(value)
+		smartLog.HasCriticalWarning = true
+	}
+
+	// nvme-cli разных версий использует разные имена полей
+	if value, ok := nvmeJSONNumber(raw, "percent_used", "percentage_used"); ok {
+		smartLog.PercentageUsed = value
+		smartLog.HasPercentageUsed = true
+	}
+
+	if value, ok := nvmeJSONNumber(raw, "data_units_written"); ok {
+		smartLog.DataUnitsWritten = value
+		smartLog.HasDataUnits = true
+	}
+
+	for _, sensor := range []string{"1", "2"} {
+		if value, ok := nvmeJSONNumber(raw, "thm_temp"+sensor+"_trans_count"); ok {
+			smartLog.ThrottleCounts[sensor] = value
+		}
+		if value, ok := nvmeJSONNumber(raw, "thm_temp"+sensor+"_total_time"); ok {
+			smartLog.ThrottleSeconds[sensor] = value
+		}
+	}
+
+	return smartLog, nil
+}
+
+// nvmeJSONNumber возвращает первое найденное числовое поле. Новые версии nvme-cli
+// могут отдавать поле объектом вида {"value": N}, поэтому поддерживаются оба формата.
+func nvmeJSONNumber(raw map[string]json.RawMessage, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		value, ok := raw[key]
+		if !ok {
+			continue
+		}
+
+		var number json.Number
+		if err := json.Unmarshal(value, &number); err == nil {
+			if parsed, err := number.Float64(); err == nil {
+				return parsed, true
+			}
+		}
+
+		var nested struct {
+			Value json.Number `json:"value"`
+		}
+		if err := json.Unmarshal(value, &nested); err == nil && nested.Value != "" {
+			if parsed, err := nested.Value.Float64(); err == nil {
+				return parsed, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// total в файлах PSI ведётся ядром с загрузки
+	PressureStallSeconds = newConstCounterVec(
+		"pressure_stall_seconds_total",
+		"Total time tasks were stalled on the resource since boot (kind = some/full) from Pressure Stall Information",
+		[]string{"resource", "kind"},
+	)
+
+	PressureStallAverage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pressure_stall_avg_percent",
+			Help: "Share of time tasks were stalled on the resource averaged over the window (10s, 60s, 300s)",
+		},
+		[]string{"resource", "kind", "window"},
+	)
+
+	PressureSupported = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pressure_supported",
+			Help: "Whether Pressure Stall Information is available for the resource (1 = supported, 0 = unsupported)",
+		},
+		[]string{"resource"},
+	)
+)
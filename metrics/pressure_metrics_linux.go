@@ -0,0 +1,106 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const pressureRoot = "/proc/pressure"
+
+var pressureResources = []string{"cpu", "memory", "io", "irq"}
+
+// pressureLine — строка файла PSI:
+// "some avg10=0.12 avg60=0.05 avg300=0.01 total=123456".
+type pressureLine struct {
+	Averages map[string]float64
+	TotalUs  uint64
+	HasTotal bool
+}
+
+// RecordPressureMetrics публикует PSI для cpu, memory, io и irq. Если ядро
+// собрано без CONFIG_PSI или PSI отключён (psi=0), файлы отсутствуют или
+// возвращают EOPNOTSUPP — тогда pressure_supported = 0.
+func RecordPressureMetrics() {
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		averages := newGaugeSeries(PressureStallAverage)
+
+		for {
+			recordPressure(averages, pressureRoot)
+			<-ticker.C
+		}
+	}()
+}
+
+// recordPressure читает файлы PSI из root. Время простоя публикуется как
+// абсолютное значение total, накопленное с загрузки.
+func recordPressure(averages *gaugeSeries, root string) {
+	stall := PressureStallSeconds.Batch()
+
+	for _, resource := range pressureResources {
+		data, err := os.ReadFile(filepath.Join(root, resource))
+		lines := parsePressure(string(data))
+		if err != nil || len(lines) == 0 {
+			PressureSupported.With(prometheus.Labels{"resource": resource}).Set(0)
+			continue
+		}
+		PressureSupported.With(prometheus.Labels{"resource": resource}).Set(1)
+
+		for kind, line := range lines {
+			for window, value := range line.Averages {
+				averages.Set(prometheus.Labels{"resource": resource, "kind": kind, "window": window}, value)
+			}
+			if line.HasTotal {
+				stall.Set(prometheus.Labels{"resource": resource, "kind": kind}, float64(line.TotalUs)/1e6)
+			}
+		}
+	}
+
+	averages.Flush()
+	stall.Commit()
+}
+
+// parsePressure разбирает файл /proc/pressure/<resource>. Окна avg10/avg60/avg300
+// возвращаются как 10s/60s/300s.
+func parsePressure(data string) map[string]pressureLine {
+	result := make(map[string]pressureLine)
+
+	for _, raw := range strings.Split(data, "\n") {
+		fields := strings.Fields(raw)
+		if len(fields) < 2 || (fields[0] != "some" && fields[0] != "full") {
+			continue
+		}
+
+		line := pressureLine{Averages: make(map[string]float64)}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+
+			if key == "total" {
+				if parsed, err := strconv.ParseUint(value, 10, 64); err == nil {
+					line.TotalUs, line.HasTotal = parsed, true
+				}
+				continue
+			}
+			if strings.HasPrefix(key, "avg") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					line.Averages[strings.TrimPrefix(key, "avg")+"s"] = parsed
+				}
+			}
+		}
+		result[fields[0]] = line
+	}
+
+	return result
+}
@@ -0,0 +1,69 @@
+//go:build linux
+
+package metrics
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testdata/pressure — /proc/pressure ядра без CONFIG_IRQ_TIME_ACCOUNTING:
+// файла irq нет.
+
+func TestParsePressure(t *testing.T) {
+	got := parsePressure("some avg10=1.25 avg60=0.80 avg300=0.31 total=91823344\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")
+	want := map[string]pressureLine{
+		"some": {Averages: map[string]float64{"10s": 1.25, "60s": 0.8, "300s": 0.31}, TotalUs: 91823344, HasTotal: true},
+		"full": {Averages: map[string]float64{"10s": 0, "60s": 0, "300s": 0}, HasTotal: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePressure() = %+v, want %+v", got, want)
+	}
+
+	if got := parsePressure("some avg10=abc total=-1\n"); got["some"].HasTotal || len(got["some"].Averages) != 0 {
+		t.Errorf("parsePressure(malformed) = %+v", got)
+	}
+}
+
+func TestRecordPressure(t *testing.T) {
+	PressureStallSeconds.Reset()
+	PressureStallAverage.Reset()
+	PressureSupported.Reset()
+
+	averages := newGaugeSeries(PressureStallAverage)
+
+	// время простоя с загрузки публикуется сразу
+	recordPressure(averages, filepath.Join("testdata", "pressure"))
+	want := []string{
+		"pressure_stall_seconds_total{kind=full,resource=cpu} 0",
+		"pressure_stall_seconds_total{kind=full,resource=io} 355.210442",
+		"pressure_stall_seconds_total{kind=full,resource=memory} 2.301877",
+		"pressure_stall_seconds_total{kind=some,resource=cpu} 91.823344",
+		"pressure_stall_seconds_total{kind=some,resource=io} 412.093817",
+		"pressure_stall_seconds_total{kind=some,resource=memory} 4.120511",
+		"pressure_supported{resource=cpu} 1",
+		"pressure_supported{resource=io} 1",
+		"pressure_supported{resource=irq} 0",
+		"pressure_supported{resource=memory} 1",
+	}
+	if got := gatherSeries(t, PressureStallSeconds, PressureSupported); !reflect.DeepEqual(got, want) {
+		t.Errorf("series:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if got := gatherSeries(t, PressureStallAverage); len(got) != 18 {
+		t.Errorf("published %d averages, want 18", len(got))
+	}
+
+	// PSI стал недоступен: средние и время простоя больше не публикуются
+	recordPressure(averages, t.TempDir())
+	want = []string{
+		"pressure_supported{resource=cpu} 0",
+		"pressure_supported{resource=io} 0",
+		"pressure_supported{resource=irq} 0",
+		"pressure_supported{resource=memory} 0",
+	}
+	if got := gatherSeries(t, PressureStallSeconds, PressureStallAverage, PressureSupported); !reflect.DeepEqual(got, want) {
+		t.Errorf("after PSI disabled:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
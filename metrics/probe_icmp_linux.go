@@ -0,0 +1,113 @@
+//go:build linux
+
+package metrics
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// probeICMP отправляет один echo request через непривилегированный ICMP-сокет
+// (SOCK_DGRAM). Для его использования GID процесса должен входить в диапазон
+// net.ipv4.ping_group_range либо агент должен работать от root.
+func probeICMP(ctx context.Context, host string) (probeResult, error) {
+	start := time.Now()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return probeResult{Duration: time.Since(start)}, err
+	}
+	if len(addrs) == 0 {
+		return probeResult{Duration: time.Since(start)}, fmt.Errorf("no addresses for %s", host)
+	}
+	ip := addrs[0].IP
+
+	family, proto, echoType, replyType := unix.AF_INET, unix.IPPROTO_ICMP, byte(8), byte(0)
+	var sockaddr unix.Sockaddr
+	if ip4 := ip.To4(); ip4 != nil {
+		sa := &unix.SockaddrInet4{}
+		copy(sa.Addr[:], ip4)
+		sockaddr = sa
+	} else {
+		family, proto, echoType, replyType = unix.AF_INET6, unix.IPPROTO_ICMPV6, 128, 129
+		sa := &unix.SockaddrInet6{}
+		copy(sa.Addr[:], ip.To16())
+		sockaddr = sa
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	// нулевой или отрицательный таймаут сокет понял бы как ожидание без срока
+	if !time.Now().Before(deadline) {
+		return probeResult{Duration: time.Since(start)}, fmt.Errorf("icmp probe timeout expired before sending")
+	}
+
+	// неблокирующий сокет обслуживается netpoller'ом Go, поэтому для него
+	// работает SetReadDeadline
+	fd, err := unix.Socket(family, unix.SOCK_DGRAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, proto)
+	if err != nil {
+		return probeResult{Duration: time.Since(start)}, fmt.Errorf("unprivileged icmp socket: %w", err)
+	}
+	if err := unix.Connect(fd, sockaddr); err != nil {
+		unix.Close(fd)
+		return probeResult{Duration: time.Since(start)}, err
+	}
+	file := os.NewFile(uintptr(fd), "icmp")
+	defer file.Close()
+
+	if err := file.SetDeadline(deadline); err != nil {
+		return probeResult{Duration: time.Since(start)}, fmt.Errorf("icmp socket deadline: %w", err)
+	}
+
+	// идентификатор подставляет ядро (порт сокета), контрольную сумму — тоже
+	seq := uint16(time.Now().UnixNano())
+	request := make([]byte, 16)
+	request[0] = echoType
+	binary.BigEndian.PutUint16(request[6:], seq)
+	copy(request[8:], "ncmprobe")
+	if family == unix.AF_INET {
+		binary.BigEndian.PutUint16(request[2:], icmpChecksum(request))
+	}
+
+	start = time.Now()
+	if _, err := file.Write(request); err != nil {
+		return probeResult{Duration: time.Since(start)}, err
+	}
+
+	reply := make([]byte, 1500)
+	for {
+		n, err := file.Read(reply)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return probeResult{Duration: time.Since(start)}, fmt.Errorf("icmp reply timeout")
+		}
+		if err != nil {
+			return probeResult{Duration: time.Since(start)}, err
+		}
+		if n >= 8 && reply[0] == replyType && binary.BigEndian.Uint16(reply[6:]) == seq {
+			return probeResult{Success: true, Duration: time.Since(start)}, nil
+		}
+	}
+}
+
+func icmpChecksum(data []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i:]))
+	}
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,74 @@
+//go:build linux
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// icmpAllowed проверяет, разрешён ли процессу непривилегированный ICMP-сокет
+// (net.ipv4.ping_group_range).
+func icmpAllowed(t *testing.T) {
+	t.Helper()
+
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM, unix.IPPROTO_ICMP)
+	if errors.Is(err, unix.EACCES) || errors.Is(err, unix.EPERM) || errors.Is(err, unix.EPROTONOSUPPORT) {
+		t.Skipf("unprivileged icmp is not permitted: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	unix.Close(fd)
+}
+
+func TestProbeICMPLoopback(t *testing.T) {
+	icmpAllowed(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	result, err := probeICMP(ctx, "127.0.0.1")
+	if err != nil || !result.Success {
+		t.Fatalf("probeICMP(127.0.0.1) = %+v, %v; want success", result, err)
+	}
+}
+
+func TestProbeICMPExpiredTimeout(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	// истёкший таймаут не превращается в бесконечное ожидание ответа
+	done := make(chan error, 1)
+	go func() {
+		_, err := probeICMP(ctx, "127.0.0.1")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("probeICMP() with expired deadline succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("probeICMP() with expired deadline did not return")
+	}
+}
+
+func TestICMPChecksum(t *testing.T) {
+	// echo request с id 0, seq 1 и без данных: 0x0800 0x0000 0x0000 0x0001
+	request := []byte{8, 0, 0, 0, 0, 0, 0, 1}
+	if got := icmpChecksum(request); got != 0xf7fe {
+		t.Errorf("icmpChecksum() = %#04x, want 0xf7fe", got)
+	}
+
+	// контрольная сумма пакета с записанной суммой равна нулю
+	request[2], request[3] = 0xf7, 0xfe
+	if got := icmpChecksum(request); got != 0 {
+		t.Errorf("icmpChecksum(with checksum) = %#04x, want 0", got)
+	}
+}
@@ -0,0 +1,138 @@
+//go:build windows
+
+package metrics
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	iphlpapi            = windows.NewLazySystemDLL("iphlpapi.dll")
+	procIcmpCreateFile  = iphlpapi.NewProc("IcmpCreateFile")
+	procIcmp6CreateFile = iphlpapi.NewProc("Icmp6CreateFile")
+	procIcmpCloseHandle = iphlpapi.NewProc("IcmpCloseHandle")
+	procIcmpSendEcho    = iphlpapi.NewProc("IcmpSendEcho")
+	procIcmp6SendEcho2  = iphlpapi.NewProc("Icmp6SendEcho2")
+)
+
+// icmpEchoReply — ICMP_ECHO_REPLY (ipexport.h).
+type icmpEchoReply struct {
+	Address       uint32
+	Status        uint32
+	RoundTripTime uint32
+	DataSize      uint16
+	Reserved      uint16
+	Data          uintptr
+	Options       struct {
+		TTL, Tos, Flags, OptionsSize uint8
+		OptionsData                  uintptr
+	}
+}
+
+// sockaddrIn6 — SOCKADDR_IN6 для Icmp6SendEcho2.
+type sockaddrIn6 struct {
+	Family   uint16
+	Port     uint16
+	FlowInfo uint32
+	Addr     [16]byte
+	ScopeID  uint32
+}
+
+// icmp6EchoReplyStatus — смещение Status в ICMPV6_ECHO_REPLY: перед ним
+// упакованная структура IPV6_ADDRESS_EX размером 26 байт.
+const icmp6EchoReplyStatus = 26
+
+// probeICMP отправляет один echo request через IcmpSendEcho/Icmp6SendEcho2
+// из iphlpapi.dll. В отличие от raw-сокетов эти функции не требуют прав
+// администратора.
+func probeICMP(ctx context.Context, host string) (probeResult, error) {
+	start := time.Now()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return probeResult{Duration: time.Since(start)}, err
+	}
+	if len(addrs) == 0 {
+		return probeResult{Duration: time.Since(start)}, fmt.Errorf("no addresses for %s", host)
+	}
+	ip := addrs[0].IP
+
+	timeout := 5 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if timeout < time.Millisecond {
+		return probeResult{Duration: time.Since(start)}, fmt.Errorf("icmp probe timeout expired before sending")
+	}
+
+	request := []byte("ncmprobe")
+	reply := make([]byte, int(unsafe.Sizeof(icmpEchoReply{}))+len(request)+8+64)
+
+	if ip4 := ip.To4(); ip4 != nil {
+		handle, _, err := procIcmpCreateFile.Call()
+		if windows.Handle(handle) == windows.InvalidHandle {
+			return probeResult{Duration: time.Since(start)}, fmt.Errorf("IcmpCreateFile failed: %v", err)
+		}
+		defer procIcmpCloseHandle.Call(handle)
+
+		start = time.Now()
+		// IPAddr — in_addr, байты адреса в сетевом порядке
+		count, _, err := procIcmpSendEcho.Call(
+			handle,
+			uintptr(binary.LittleEndian.Uint32(ip4)),
+			uintptr(unsafe.Pointer(&request[0])),
+			uintptr(len(request)),
+			0,
+			uintptr(unsafe.Pointer(&reply[0])),
+			uintptr(len(reply)),
+			uintptr(timeout.Milliseconds()),
+		)
+		duration := time.Since(start)
+		if count == 0 {
+			return probeResult{Duration: duration}, fmt.Errorf("IcmpSendEcho failed: %v", err)
+		}
+		if status := (*icmpEchoReply)(unsafe.Pointer(&reply[0])).Status; status != 0 {
+			return probeResult{Duration: duration}, fmt.Errorf("icmp echo status %d", status)
+		}
+		return probeResult{Success: true, Duration: duration}, nil
+	}
+
+	handle, _, err := procIcmp6CreateFile.Call()
+	if windows.Handle(handle) == windows.InvalidHandle {
+		return probeResult{Duration: time.Since(start)}, fmt.Errorf("Icmp6CreateFile failed: %v", err)
+	}
+	defer procIcmpCloseHandle.Call(handle)
+
+	source := sockaddrIn6{Family: windows.AF_INET6}
+	destination := sockaddrIn6{Family: windows.AF_INET6}
+	copy(destination.Addr[:], ip.To16())
+
+	start = time.Now()
+	count, _, err := procIcmp6SendEcho2.Call(
+		handle,
+		0, 0, 0,
+		uintptr(unsafe.Pointer(&source)),
+		uintptr(unsafe.Pointer(&destination)),
+		uintptr(unsafe.Pointer(&request[0])),
+		uintptr(len(request)),
+		0,
+		uintptr(unsafe.Pointer(&reply[0])),
+		uintptr(len(reply)),
+		uintptr(timeout.Milliseconds()),
+	)
+	duration := time.Since(start)
+	if count == 0 {
+		return probeResult{Duration: duration}, fmt.Errorf("Icmp6SendEcho2 failed: %v", err)
+	}
+	if status := binary.LittleEndian.Uint32(reply[icmp6EchoReplyStatus:]); status != 0 {
+		return probeResult{Duration: duration}, fmt.Errorf("icmp echo status %d", status)
+	}
+	return probeResult{Success: true, Duration: duration}, nil
+}
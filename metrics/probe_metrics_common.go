@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"node_exporter_custom/internal/deviceconfig"
+)
+
+var (
+	ProbeSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "probe_success",
+			Help: "Whether the connectivity probe succeeded (1) or failed (0)",
+		},
+		[]string{"name", "module", "target"},
+	)
+
+	ProbeDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "probe_duration_seconds",
+			Help: "Duration of the connectivity probe in seconds",
+		},
+		[]string{"name", "module", "target"},
+	)
+
+	ProbeTLSCertExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "probe_ssl_earliest_cert_expiry",
+			Help: "Unix timestamp of the earliest expiring certificate in the TLS chain of the probed target",
+		},
+		[]string{"name", "module", "target"},
+	)
+)
+
+type probeResult struct {
+	Success    bool
+	Duration   time.Duration
+	CertExpiry time.Time
+}
+
+// RecordProbes периодически проверяет доступность целей из конфигурации агента.
+// Все цели опрашиваются параллельно, каждая — с таймаутом из конфигурации.
+func RecordProbes(cfg deviceconfig.ProbesConfig) {
+	if len(cfg.Targets) == 0 {
+		return
+	}
+
+	interval := cfg.EffectiveInterval()
+	timeout := cfg.EffectiveTimeout()
+	log.Printf("connectivity probes started: %d targets, interval %s", len(cfg.Targets), interval)
+
+	for _, target := range cfg.Targets {
+		go func(target deviceconfig.ProbeTarget) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				recordProbe(target, timeout)
+				<-ticker.C
+			}
+		}(target)
+	}
+}
+
+func recordProbe(target deviceconfig.ProbeTarget, timeout time.Duration) {
+	module := strings.ToLower(target.Module)
+	name := target.Name
+	if name == "" {
+		name = target.Target
+	}
+	labels := prometheus.Labels{"name": name, "module": module, "target": target.Target}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := runProbe(ctx, module, target)
+	if err != nil {
+		log.Printf("probe %s (%s %s) failed: %v", name, module, target.Target, err)
+	}
+
+	success := 0.0
+	if result.Success {
+		success = 1
+	}
+	ProbeSuccess.With(labels).Set(success)
+	ProbeDuration.With(labels).Set(result.Duration.Seconds())
+
+	if !result.CertExpiry.IsZero() {
+		ProbeTLSCertExpiry.With(labels).Set(float64(result.CertExpiry.Unix()))
+	} else {
+		ProbeTLSCertExpiry.Delete(labels)
+	}
+}
+
+func runProbe(ctx context.Context, module string, target deviceconfig.ProbeTarget) (probeResult, error) {
+	switch module {
+	case "icmp":
+		return probeICMP(ctx, target.Target)
+	case "tcp":
+		return probeTCP(ctx, target.Target)
+	case "http", "https":
+		return probeHTTP(ctx, target.Target, target.InsecureSkipVerify)
+	default:
+		return probeResult{}, fmt.Errorf("unknown probe module %q", module)
+	}
+}
+
+func probeTCP(ctx context.Context, address string) (probeResult, error) {
+	start := time.Now()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	result := probeResult{Duration: time.Since(start)}
+	if err != nil {
+		return result, err
+	}
+	conn.Close()
+
+	result.Success = true
+	return result, nil
+}
+
+func probeHTTP(ctx context.Context, url string, insecureSkipVerify bool) (probeResult, error) {
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return probeResult{}, err
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: insecureSkipVerify},
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return probeResult{Duration: time.Since(start)}, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	result := probeResult{Duration: time.Since(start)}
+	if resp.TLS != nil {
+		result.CertExpiry = earliestCertExpiry(resp.TLS)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 399 {
+		return result, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+func earliestCertExpiry(state *tls.ConnectionState) time.Time {
+	var earliest time.Time
+	for _, cert := range state.PeerCertificates {
+		if earliest.IsZero() || cert.NotAfter.Before(earliest) {
+			earliest = cert.NotAfter
+		}
+	}
+	return earliest
+}
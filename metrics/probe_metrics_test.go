@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"node_exporter_custom/internal/deviceconfig"
+)
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	result, err := probeTCP(context.Background(), listener.Addr().String())
+	if err != nil || !result.Success {
+		t.Fatalf("probeTCP(open port) = %+v, %v; want success", result, err)
+	}
+
+	// порт, который только что освободили, гарантированно никто не слушает
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := closed.Addr().String()
+	closed.Close()
+
+	result, err = probeTCP(context.Background(), address)
+	if err == nil || result.Success {
+		t.Fatalf("probeTCP(closed port) = %+v, %v; want failure", result, err)
+	}
+}
+
+func TestProbeHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status  int
+		success bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusNoContent, true},
+		{http.StatusNotModified, true},
+		{http.StatusNotFound, false},
+		{http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		result, err := probeHTTP(context.Background(), server.URL, false)
+		server.Close()
+
+		if result.Success != tt.success || (err == nil) != tt.success {
+			t.Errorf("status %d: got success=%v err=%v, want success=%v", tt.status, result.Success, err, tt.success)
+		}
+		if !result.CertExpiry.IsZero() {
+			t.Errorf("status %d: cert expiry %v reported for plain HTTP", tt.status, result.CertExpiry)
+		}
+	}
+}
+
+func TestProbeHTTPSCertExpiry(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// самоподписанный сертификат httptest не проходит проверку без insecure_skip_verify
+	result, err := probeHTTP(context.Background(), server.URL, false)
+	if err == nil || result.Success {
+		t.Fatalf("probeHTTP(untrusted cert) = %+v, %v; want failure", result, err)
+	}
+
+	result, err = probeHTTP(context.Background(), server.URL, true)
+	if err != nil || !result.Success {
+		t.Fatalf("probeHTTP(insecure) = %+v, %v; want success", result, err)
+	}
+
+	want := server.Certificate().NotAfter
+	if !result.CertExpiry.Equal(want) {
+		t.Errorf("cert expiry = %v, want %v", result.CertExpiry, want)
+	}
+}
+
+func TestProbeHTTPTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, err := runProbe(ctx, "http", deviceconfig.ProbeTarget{Target: server.URL})
+	if err == nil || result.Success {
+		t.Fatalf("runProbe(slow server) = %+v, %v; want failure", result, err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("probe took %v, timeout was not applied", elapsed)
+	}
+}
+
+func TestRunProbeUnknownModule(t *testing.T) {
+	if _, err := runProbe(context.Background(), "ftp", deviceconfig.ProbeTarget{Target: "example.com:21"}); err == nil {
+		t.Error("runProbe(ftp) succeeded, want unknown module error")
+	}
+}
@@ -8,22 +8,24 @@ import (
 	"strings"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"
 	"github.com/shirou/gopsutil/process"
+
+	"node_exporter_custom/internal/deviceconfig"
 )
 
-type processTotals struct {
-	count      int
-	rssMB      float64
-	privateMB  float64
-	cpuPercent float64
-}
+func RecordProccessInfo(cfg deviceconfig.ProcessesConfig) {
+	selector := newProcessSelector(cfg)
 
-func RecordProccessInfo() {
 	go func() {
 		ticker := time.NewTicker(5 * time.Second)
 		defer ticker.Stop()
 
+		// объекты process.Process создаются заново каждый цикл, поэтому их
+		// CPUPercent даёт среднюю загрузку за всё время жизни процесса;
+		// загрузка за интервал считается по сохранённому между циклами времени CPU
+		cpuTracker := newProcessCPUTracker()
+		series := newProcessSeries()
+
 		for {
 			processes, err := process.Processes()
 			if err != nil {
@@ -33,65 +35,64 @@ func RecordProccessInfo() {
 			}
 
 			ProccessCount.Set(float64(len(processes)))
+			now := time.Now()
 
-			aggregates := make(map[string]*processTotals)
+			var samples []processSample
+			aggregates := make(map[string]processTotals)
 
 			for _, proc := range processes {
 				pid := proc.Pid
-				name := processName(proc, pid)
-
-				labels := prometheus.Labels{"process": name, "pid": fmt.Sprintf("%d", pid)}
+				sample := processSample{
+					PID:  fmt.Sprintf("%d", pid),
+					Name: processName(proc, pid),
+				}
+				if selector.needsCmdline() {
+					sample.Cmdline, _ = proc.Cmdline()
+				}
+				if selector.needsUser() {
+					sample.User, _ = proc.Username()
+				}
+				if !selector.allowed(sample) {
+					continue
+				}
 
+				hasMemory, hasCPU := false, false
 				if memInfo, err := proc.MemoryInfoEx(); err == nil {
-					rssMB := float64(memInfo.RSS) / (1024 * 1024)
 					privateBytes := memInfo.RSS
 					if memInfo.Shared < memInfo.RSS {
 						privateBytes = memInfo.RSS - memInfo.Shared
 					}
-					privMB := float64(privateBytes) / (1024 * 1024)
-
-					ProccessMemoryUsage.With(labels).Set(rssMB)
+					sample.MemoryMB = float64(memInfo.RSS) / (1024 * 1024)
+					sample.PrivateMB = float64(privateBytes) / (1024 * 1024)
+					hasMemory = true
+				}
 
-					agg := aggregates[name]
-					if agg == nil {
-						agg = &processTotals{}
-						aggregates[name] = agg
+				if times, err := proc.Times(); err == nil {
+					if createTime, err := proc.CreateTime(); err == nil {
+						sample.CPU = cpuTracker.Percent(sample.PID, fmt.Sprintf("%d", createTime),
+							times.User, times.System, now)
+						hasCPU = true
 					}
-					agg.count++
-					agg.rssMB += rssMB
-					agg.privateMB += privMB
 				}
 
-				if cpuPercent, err := proc.CPUPercent(); err == nil {
-					ProccessCPUUsage.With(labels).Set(cpuPercent)
-
-					agg := aggregates[name]
-					if agg == nil {
-						agg = &processTotals{}
-						aggregates[name] = agg
-					}
-					if agg.count == 0 {
-						agg.count = 1
-					}
-					agg.cpuPercent += cpuPercent
+				if !hasMemory && !hasCPU {
+					continue
 				}
+				samples = append(samples, sample)
+
+				agg := aggregates[sample.Name]
+				agg.count++
+				agg.rssMB += sample.MemoryMB
+				agg.privateMB += sample.PrivateMB
+				agg.cpuPercent += sample.CPU
+				aggregates[sample.Name] = agg
 			}
 
-			for name, totals := range aggregates {
-				ProcessInstanceCount.With(prometheus.Labels{"process": name}).Set(float64(totals.count))
-				ProcessGroupMemoryWorkingSet.With(prometheus.Labels{
-					"process":   name,
-					"instances": fmt.Sprintf("%d", totals.count),
-				}).Set(totals.rssMB)
-				ProcessGroupMemoryPrivate.With(prometheus.Labels{
-					"process":   name,
-					"instances": fmt.Sprintf("%d", totals.count),
-				}).Set(totals.privateMB)
-				ProcessGroupCPUUsage.With(prometheus.Labels{
-					"process":   name,
-					"instances": fmt.Sprintf("%d", totals.count),
-				}).Set(totals.cpuPercent)
-			}
+			selected := series.recordProcesses(selector, samples)
+			cpuTracker.Publish(selected)
+			cpuTracker.Prune(now.Add(-time.Minute))
+
+			series.recordGroups(selector, aggregates)
 
 			<-ticker.C
 		}
@@ -9,8 +9,10 @@ import (
 	"time"
 	"unsafe"
 
-	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shirou/gopsutil/process"
 	"golang.org/x/sys/windows"
+
+	"node_exporter_custom/internal/deviceconfig"
 )
 
 // Metric declarations live in process_metrics_common.go
@@ -118,6 +120,11 @@ func getProcessList() ([]ProcessInfo, error) {
 	return processes, nil
 }
 
+type processTimes struct {
+	creation uint64
+	total    uint64
+}
+
 func filetimeToUint64(ft windows.Filetime) uint64 {
 	return (uint64(ft.HighDateTime) << 32) | uint64(ft.LowDateTime)
 }
@@ -130,6 +137,41 @@ func cleanupHandles(processes []ProcessInfo) {
 	}
 }
 
+// processUser возвращает владельца процесса в виде DOMAIN\user. Используется
+// только фильтрами по пользователю, так как запрос токена относительно дорог.
+func processUser(handle windows.Handle) string {
+	var token windows.Token
+	if err := windows.OpenProcessToken(handle, windows.TOKEN_QUERY, &token); err != nil {
+		return ""
+	}
+	defer token.Close()
+
+	tokenUser, err := token.GetTokenUser()
+	if err != nil {
+		return ""
+	}
+
+	account, domain, _, err := tokenUser.User.Sid.LookupAccount("")
+	if err != nil {
+		return tokenUser.User.Sid.String()
+	}
+	if domain == "" {
+		return account
+	}
+	return domain + `\` + account
+}
+
+// processCmdline возвращает командную строку процесса из его PEB. Для
+// защищённых и системных процессов без прав администратора она недоступна,
+// тогда возвращается пустая строка и фильтр по cmdline с ней не совпадёт.
+func processCmdline(pid uint32) string {
+	cmdline, err := (&process.Process{Pid: int32(pid)}).Cmdline()
+	if err != nil {
+		return ""
+	}
+	return cmdline
+}
+
 // Безопасно получает системное время
 func getSystemTimeSafe() (uint64, error) {
 	kernel32 := windows.NewLazySystemDLL("kernel32.dll")
@@ -149,11 +191,17 @@ func getSystemTimeSafe() (uint64, error) {
 	return filetimeToUint64(kernelTime) + filetimeToUint64(userTime), nil
 }
 
-func RecordProccessInfo() {
+func RecordProccessInfo(cfg deviceconfig.ProcessesConfig) {
+	selector := newProcessSelector(cfg)
+
 	go func() {
 		// Инициализация структур для отслеживания времени
-		prevProcessTimes := make(map[uint32]uint64)
+		// время CPU хранится вместе с временем создания процесса, чтобы
+		// повторно использованный PID не давал ложного приращения
+		prevProcessTimes := make(map[uint32]processTimes)
 		var prevSystemTime uint64
+		cpuTracker := newProcessCPUTracker()
+		series := newProcessSeries()
 		var mutex sync.Mutex // Для безопасного доступа к prevProcessTimes
 
 		// Инициализация PSAPI
@@ -189,9 +237,20 @@ func RecordProccessInfo() {
 			ProccessCount.Set(float64(totalProcesses))
 			log.Printf("Total active processes: %d", totalProcesses)
 
-			// Группируем процессы по имени для подсчета экземпляров и суммирования ресурсов
+			// Группируем процессы по имени для подсчета экземпляров и суммирования ресурсов.
+			// Процессы, не прошедшие фильтры include/exclude, не учитываются.
 			processGroups := make(map[string][]ProcessInfo)
 			for _, proc := range processes {
+				sample := processSample{PID: fmt.Sprint(proc.PID), Name: proc.Name}
+				if selector.needsCmdline() {
+					sample.Cmdline = processCmdline(proc.PID)
+				}
+				if selector.needsUser() && proc.HasHandle {
+					sample.User = processUser(proc.Handle)
+				}
+				if !selector.allowed(sample) {
+					continue
+				}
 				processGroups[proc.Name] = append(processGroups[proc.Name], proc)
 			}
 
@@ -199,10 +258,6 @@ func RecordProccessInfo() {
 			log.Printf("Process instance counts:")
 			for name, procs := range processGroups {
 				count := len(procs)
-				ProcessInstanceCount.With(prometheus.Labels{
-					"process": name,
-				}).Set(float64(count))
-
 				if count > 1 {
 					log.Printf("  %s: %d instances", name, count)
 				}
@@ -217,7 +272,7 @@ func RecordProccessInfo() {
 				if systemTimesErrorCount >= 3 {
 					log.Printf("Too many GetSystemTimes errors, resetting counters")
 					mutex.Lock()
-					prevProcessTimes = make(map[uint32]uint64)
+					prevProcessTimes = make(map[uint32]processTimes)
 					prevSystemTime = 0
 					mutex.Unlock()
 					systemTimesErrorCount = 0
@@ -237,135 +292,141 @@ func RecordProccessInfo() {
 			totalMemoryPrivate := make(map[string]float64)
 			totalCPU := make(map[string]float64)
 
-			// Обрабатываем каждый процесс
-			for _, proc := range processes {
-				currentPIDs[proc.PID] = true
+			var samples []processSample
 
-				if !proc.HasHandle {
-					continue
-				}
+			// Обрабатываем каждый процесс
+			for _, procs := range processGroups {
+				for _, proc := range procs {
+					currentPIDs[proc.PID] = true
 
-				// Получение информации о CPU
-				var creation, exit, kernel, user windows.Filetime
-				err = windows.GetProcessTimes(
-					proc.Handle,
-					&creation,
-					&exit,
-					&kernel,
-					&user,
-				)
-
-				if err != nil {
-					continue
-				}
+					if !proc.HasHandle {
+						continue
+					}
 
-				currentProcessTime := filetimeToUint64(kernel) + filetimeToUint64(user)
+					// Получение информации о CPU
+					var creation, exit, kernel, user windows.Filetime
+					err = windows.GetProcessTimes(
+						proc.Handle,
+						&creation,
+						&exit,
+						&kernel,
+						&user,
+					)
+
+					if err != nil {
+						continue
+					}
 
-				// Расчет загрузки CPU
-				cpuUsage := 0.0
-				mutex.Lock()
-				if prevSystemTime > 0 && prevProcessTimes[proc.PID] > 0 {
-					timeDelta := currentSystemTime - prevSystemTime
-					processDelta := currentProcessTime - prevProcessTimes[proc.PID]
+					currentProcessTime := filetimeToUint64(kernel) + filetimeToUint64(user)
+					creationTime := filetimeToUint64(creation)
 
-					if timeDelta > 0 {
-						cpuUsage = (float64(processDelta) / float64(timeDelta)) * 100.0
+					// FILETIME считается в интервалах по 100 нс
+					cpuTracker.Observe(fmt.Sprint(proc.PID), fmt.Sprint(creationTime),
+						float64(filetimeToUint64(user))/1e7, float64(filetimeToUint64(kernel))/1e7, time.Now())
 
-						// Нормализуем по количеству ядер
-						if cpuUsage > 0 {
-							cpuUsage = cpuUsage / cpuCores
+					// Расчет загрузки CPU
+					cpuUsage := 0.0
+					mutex.Lock()
+					prev, seen := prevProcessTimes[proc.PID]
+					if prevSystemTime > 0 && seen && prev.creation == creationTime && currentProcessTime >= prev.total {
+						timeDelta := currentSystemTime - prevSystemTime
+						processDelta := currentProcessTime - prev.total
+
+						if timeDelta > 0 {
+							cpuUsage = (float64(processDelta) / float64(timeDelta)) * 100.0
+
+							// Нормализуем по количеству ядер
+							if cpuUsage > 0 {
+								cpuUsage = cpuUsage / cpuCores
+							}
+
+							// Ограничиваем максимальное значение до 100%
+							if cpuUsage > 100.0 {
+								cpuUsage = 100.0
+							}
 						}
+					}
+					prevProcessTimes[proc.PID] = processTimes{creation: creationTime, total: currentProcessTime}
+					mutex.Unlock()
 
-						// Ограничиваем максимальное значение до 100%
-						if cpuUsage > 100.0 {
-							cpuUsage = 100.0
-						}
+					// Получение информации о памяти
+					var memInfo PROCESS_MEMORY_COUNTERS_EX
+					memInfo.CB = uint32(unsafe.Sizeof(memInfo))
+					ret, _, _ := getProcessMemoryInfo.Call(
+						uintptr(proc.Handle),
+						uintptr(unsafe.Pointer(&memInfo)),
+						uintptr(memInfo.CB),
+					)
+
+					// Преобразуем байты в мегабайты
+					var workingSetMB, privateMB float64
+
+					if ret == 0 {
+						// Если не удалось получить информацию о памяти, используем нулевые значения
+						// но продолжаем обработку процесса
+						workingSetMB = 0
+						privateMB = 0
+					} else {
+						workingSetMB = float64(memInfo.WorkingSetSize) / (1024 * 1024)
+						privateMB = float64(memInfo.PrivateUsage) / (1024 * 1024)
+					}
+
+					// Суммируем ресурсы по группам процессов
+					totalMemoryWorkingSet[proc.Name] += workingSetMB
+					totalMemoryPrivate[proc.Name] += privateMB
+					totalCPU[proc.Name] += cpuUsage
+
+					// Логируем только для важных процессов или с высоким использованием ресурсов
+					if cpuUsage > 0.5 || workingSetMB > 100.0 {
+						log.Printf("Process: %s (PID: %d) - Memory: WorkingSet=%.2f MB, Private=%.2f MB, CPU: %.2f%%",
+							proc.Name,
+							proc.PID,
+							workingSetMB,
+							privateMB,
+							cpuUsage)
 					}
-				}
-				prevProcessTimes[proc.PID] = currentProcessTime
-				mutex.Unlock()
-
-				// Получение информации о памяти
-				var memInfo PROCESS_MEMORY_COUNTERS_EX
-				memInfo.CB = uint32(unsafe.Sizeof(memInfo))
-				ret, _, _ := getProcessMemoryInfo.Call(
-					uintptr(proc.Handle),
-					uintptr(unsafe.Pointer(&memInfo)),
-					uintptr(memInfo.CB),
-				)
-
-				// Преобразуем байты в мегабайты
-				var workingSetMB, privateMB float64
-
-				if ret == 0 {
-					// Если не удалось получить информацию о памяти, используем нулевые значения
-					// но продолжаем обработку процесса
-					workingSetMB = 0
-					privateMB = 0
-				} else {
-					workingSetMB = float64(memInfo.WorkingSetSize) / (1024 * 1024)
-					privateMB = float64(memInfo.PrivateUsage) / (1024 * 1024)
-				}
 
-				// Суммируем ресурсы по группам процессов
-				totalMemoryWorkingSet[proc.Name] += workingSetMB
-				totalMemoryPrivate[proc.Name] += privateMB
-				totalCPU[proc.Name] += cpuUsage
-
-				// Логируем только для важных процессов или с высоким использованием ресурсов
-				if cpuUsage > 0.5 || workingSetMB > 100.0 {
-					log.Printf("Process: %s (PID: %d) - Memory: WorkingSet=%.2f MB, Private=%.2f MB, CPU: %.2f%%",
-						proc.Name,
-						proc.PID,
-						workingSetMB,
-						privateMB,
-						cpuUsage)
+					// Метрики по PID публикуются только для процессов с реальными данными
+					// (чтобы не засорять Prometheus нулевыми значениями)
+					if workingSetMB > 0 || privateMB > 0 || cpuUsage > 0 {
+						samples = append(samples, processSample{
+							PID:       fmt.Sprint(proc.PID),
+							Name:      proc.Name,
+							CPU:       cpuUsage,
+							MemoryMB:  workingSetMB, // Используем WorkingSetSize как в Task Manager
+							PrivateMB: privateMB,
+						})
+					}
 				}
+			}
+
+			selected := series.recordProcesses(selector, samples)
+			cpuTracker.Publish(selected)
+			cpuTracker.Prune(time.Now().Add(-time.Minute))
 
-				// Устанавливаем метрики для каждого процесса только если есть реальные данные
-				// (чтобы не засорять Prometheus нулевыми значениями)
-				if workingSetMB > 0 || privateMB > 0 || cpuUsage > 0 {
-					ProccessMemoryUsage.With(prometheus.Labels{
-						"process": proc.Name,
-						"pid":     fmt.Sprint(proc.PID),
-					}).Set(workingSetMB) // Используем WorkingSetSize как в Task Manager
-
-					ProccessCPUUsage.With(prometheus.Labels{
-						"process": proc.Name,
-						"pid":     fmt.Sprint(proc.PID),
-					}).Set(cpuUsage)
+			totals := make(map[string]processTotals, len(processGroups))
+			for name, procs := range processGroups {
+				totals[name] = processTotals{
+					count:      len(procs),
+					rssMB:      totalMemoryWorkingSet[name],
+					privateMB:  totalMemoryPrivate[name],
+					cpuPercent: totalCPU[name],
 				}
 			}
+			// группы сверх max_series не публикуются
+			series.recordGroups(selector, totals)
 
 			// Логируем агрегированные данные для групп процессов с несколькими экземплярами
 			log.Printf("Aggregated process resource usage:")
-			for name, procs := range processGroups {
-				instanceCount := len(procs)
-
-				// Устанавливаем метрики для всех процессов, даже с одним экземпляром
-				ProcessGroupMemoryWorkingSet.With(prometheus.Labels{
-					"process":   name,
-					"instances": fmt.Sprint(instanceCount),
-				}).Set(totalMemoryWorkingSet[name])
-
-				ProcessGroupMemoryPrivate.With(prometheus.Labels{
-					"process":   name,
-					"instances": fmt.Sprint(instanceCount),
-				}).Set(totalMemoryPrivate[name])
-
-				ProcessGroupCPUUsage.With(prometheus.Labels{
-					"process":   name,
-					"instances": fmt.Sprint(instanceCount),
-				}).Set(totalCPU[name])
-
+			for name, total := range totals {
 				// Логируем только процессы с несколькими экземплярами или значительным использованием ресурсов
-				if instanceCount > 1 || totalMemoryWorkingSet[name] > 50 || totalCPU[name] > 1.0 {
+				if total.count > 1 || total.rssMB > 50 || total.cpuPercent > 1.0 {
 					log.Printf("  %s (%d instances) - Total Memory: WorkingSet=%.2f MB, Private=%.2f MB, Total CPU: %.2f%%",
 						name,
-						instanceCount,
-						totalMemoryWorkingSet[name],
-						totalMemoryPrivate[name],
-						totalCPU[name])
+						total.count,
+						total.rssMB,
+						total.privateMB,
+						total.cpuPercent)
 				}
 			}
 
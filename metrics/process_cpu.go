@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// processCPUSample — накопленное процессорное время процесса на момент опроса.
+type processCPUSample struct {
+	start  string
+	total  float64
+	system float64
+	user   float64
+	at     time.Time
+}
+
+// processCPUTracker хранит накопленное процессорное время процессов между
+// циклами сбора. Процесс идентифицируется парой PID и времени запуска, чтобы
+// повторно использованный PID не давал отрицательного или завышенного приращения.
+// Трекер не потокобезопасен и используется из одной горутины сборщика.
+type processCPUTracker struct {
+	samples map[string]processCPUSample
+}
+
+func newProcessCPUTracker() *processCPUTracker {
+	return &processCPUTracker{samples: make(map[string]processCPUSample)}
+}
+
+// Percent запоминает процессорное время процесса и возвращает загрузку CPU за
+// интервал с предыдущего опроса (100% — одно ядро). Для процесса, который ещё
+// не наблюдался, возвращается 0: средняя загрузка за всё время жизни занижает
+// всплески долгоживущих процессов и завышает загрузку только что запущенных.
+func (t *processCPUTracker) Percent(pid, start string, user, system float64, now time.Time) float64 {
+	total := user + system
+	prev, ok := t.Observe(pid, start, user, system, now)
+	if !ok {
+		return 0
+	}
+
+	elapsed := now.Sub(prev.at).Seconds()
+	if elapsed <= 0 || total < prev.total {
+		return 0
+	}
+	return (total - prev.total) / elapsed * 100
+}
+
+// Observe запоминает процессорное время процесса и возвращает предыдущее
+// наблюдение того же процесса, если оно было.
+func (t *processCPUTracker) Observe(pid, start string, user, system float64, now time.Time) (processCPUSample, bool) {
+	prev, ok := t.samples[pid]
+	t.samples[pid] = processCPUSample{start: start, total: user + system, user: user, system: system, at: now}
+	return prev, ok && prev.start == start
+}
+
+// Publish публикует process_pid_cpu_seconds_total для отобранных процессов.
+// Значение счётчика — накопленное время процесса целиком; если PID занят новым
+// процессом, значение уменьшается, и Prometheus учитывает это как сброс
+// счётчика. Серии завершившихся и отброшенных процессов удаляются.
+func (t *processCPUTracker) Publish(selected []processSample) {
+	batch := ProcessPidCPUSeconds.Batch()
+
+	for _, sample := range selected {
+		state, ok := t.samples[sample.PID]
+		if !ok {
+			continue
+		}
+		batch.Set(prometheus.Labels{"process": sample.Name, "pid": sample.PID, "mode": "user"}, state.user)
+		batch.Set(prometheus.Labels{"process": sample.Name, "pid": sample.PID, "mode": "system"}, state.system)
+	}
+
+	batch.Commit()
+}
+
+// Prune удаляет процессы, которые не наблюдались с момента before.
+func (t *processCPUTracker) Prune(before time.Time) {
+	for pid, sample := range t.samples {
+		if sample.at.Before(before) {
+			delete(t.samples, pid)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessCPUTrackerPercent(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tracker := newProcessCPUTracker()
+
+	// первое наблюдение: интервала ещё нет, средняя за время жизни не подставляется
+	if got := tracker.Percent("100", "1", 300, 100, start); got != 0 {
+		t.Errorf("first sample = %v, want 0", got)
+	}
+
+	// 2.5 с CPU за 5 с — половина ядра
+	if got := tracker.Percent("100", "1", 302, 100.5, start.Add(5*time.Second)); got != 50 {
+		t.Errorf("second sample = %v, want 50", got)
+	}
+
+	// PID повторно использован другим процессом
+	if got := tracker.Percent("100", "2", 1, 0, start.Add(10*time.Second)); got != 0 {
+		t.Errorf("reused PID = %v, want 0", got)
+	}
+	if got := tracker.Percent("100", "2", 3, 1, start.Add(14*time.Second)); got != 75 {
+		t.Errorf("reused PID second sample = %v, want 75", got)
+	}
+}
+
+func TestProcessCPUTrackerPublish(t *testing.T) {
+	ProcessPidCPUSeconds.Reset()
+
+	start := time.Unix(1700000000, 0)
+	tracker := newProcessCPUTracker()
+	gather := func() []string {
+		return gatherSeries(t, ProcessPidCPUSeconds)
+	}
+
+	// время CPU, накопленное до запуска агента, видно сразу
+	tracker.Observe("100", "1", 300, 100, start)
+	tracker.Observe("200", "1", 20, 5, start)
+	tracker.Publish([]processSample{{PID: "100", Name: "nginx"}, {PID: "200", Name: "sshd"}})
+	want := []string{
+		"process_pid_cpu_seconds_total{mode=system,pid=100,process=nginx} 100",
+		"process_pid_cpu_seconds_total{mode=system,pid=200,process=sshd} 5",
+		"process_pid_cpu_seconds_total{mode=user,pid=100,process=nginx} 300",
+		"process_pid_cpu_seconds_total{mode=user,pid=200,process=sshd} 20",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("first cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// sshd выпал из top-N: его серии удаляются; PID 100 занят новым процессом
+	// с тем же именем, его время публикуется заново (сброс счётчика)
+	tracker.Observe("100", "2", 1, 0.5, start.Add(5*time.Second))
+	tracker.Observe("200", "1", 21, 5, start.Add(5*time.Second))
+	tracker.Publish([]processSample{{PID: "100", Name: "nginx"}})
+	want = []string{
+		"process_pid_cpu_seconds_total{mode=system,pid=100,process=nginx} 0.5",
+		"process_pid_cpu_seconds_total{mode=user,pid=100,process=nginx} 1",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after PID reuse:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// PID 100 сменил образ (exec): серия со старым именем удаляется
+	tracker.Observe("100", "2", 2, 0.5, start.Add(10*time.Second))
+	tracker.Observe("200", "1", 22, 5, start.Add(10*time.Second))
+	tracker.Publish([]processSample{{PID: "100", Name: "php-fpm"}, {PID: "200", Name: "sshd"}})
+	want = []string{
+		"process_pid_cpu_seconds_total{mode=system,pid=100,process=php-fpm} 0.5",
+		"process_pid_cpu_seconds_total{mode=system,pid=200,process=sshd} 5",
+		"process_pid_cpu_seconds_total{mode=user,pid=100,process=php-fpm} 2",
+		"process_pid_cpu_seconds_total{mode=user,pid=200,process=sshd} 22",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("after exec:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,204 @@
+package metrics
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"node_exporter_custom/internal/deviceconfig"
+)
+
+// processSample — данные одного процесса за цикл сбора.
+type processSample struct {
+	PID       string
+	Name      string
+	Cmdline   string
+	User      string
+	CPU       float64
+	MemoryMB  float64
+	PrivateMB float64
+}
+
+type processFilter struct {
+	name    *regexp.Regexp
+	cmdline *regexp.Regexp
+	user    *regexp.Regexp
+}
+
+// processSelector применяет фильтры, top-N и предел числа серий из
+// deviceconfig.ProcessesConfig.
+type processSelector struct {
+	include   []processFilter
+	exclude   []processFilter
+	topN      int
+	topBy     string
+	maxSeries int
+	// фильтры по командной строке и владельцу, вычисляются один раз
+	cmdline bool
+	user    bool
+}
+
+func newProcessSelector(cfg deviceconfig.ProcessesConfig) *processSelector {
+	selector := &processSelector{
+		include:   compileProcessFilters(cfg.Include),
+		exclude:   compileProcessFilters(cfg.Exclude),
+		topN:      cfg.TopN,
+		topBy:     strings.ToLower(cfg.TopBy),
+		maxSeries: cfg.EffectiveMaxSeries(),
+	}
+	if selector.topBy != "memory" {
+		selector.topBy = "cpu"
+	}
+	for _, filter := range append(append([]processFilter{}, selector.include...), selector.exclude...) {
+		selector.cmdline = selector.cmdline || filter.cmdline != nil
+		selector.user = selector.user || filter.user != nil
+	}
+	return selector
+}
+
+// compileProcessFilters компилирует фильтры; фильтр с некорректным выражением
+// пропускается с записью в лог, чтобы опечатка в конфигурации не отключала сбор.
+func compileProcessFilters(filters []deviceconfig.ProcessFilter) []processFilter {
+	var result []processFilter
+
+	for _, filter := range filters {
+		compiled := processFilter{}
+		valid := true
+		for _, field := range []struct {
+			pattern string
+			target  **regexp.Regexp
+		}{
+			{filter.Name, &compiled.name},
+			{filter.Cmdline, &compiled.cmdline},
+			{filter.User, &compiled.user},
+		} {
+			if field.pattern == "" {
+				continue
+			}
+			re, err := regexp.Compile(field.pattern)
+			if err != nil {
+				log.Printf("invalid process filter %q: %v", field.pattern, err)
+				valid = false
+				break
+			}
+			*field.target = re
+		}
+
+		if valid && (compiled.name != nil || compiled.cmdline != nil || compiled.user != nil) {
+			result = append(result, compiled)
+		}
+	}
+
+	return result
+}
+
+func (f processFilter) matches(p processSample) bool {
+	if f.name != nil && !f.name.MatchString(p.Name) {
+		return false
+	}
+	if f.cmdline != nil && !f.cmdline.MatchString(p.Cmdline) {
+		return false
+	}
+	if f.user != nil && !f.user.MatchString(p.User) {
+		return false
+	}
+	return true
+}
+
+// needsCmdline и needsUser позволяют не читать командную строку и владельца
+// процесса, если фильтры их не используют.
+func (s *processSelector) needsCmdline() bool {
+	return s.cmdline
+}
+
+func (s *processSelector) needsUser() bool {
+	return s.user
+}
+
+// allowed сообщает, проходит ли процесс фильтры include/exclude.
+func (s *processSelector) allowed(p processSample) bool {
+	if len(s.include) > 0 {
+		included := false
+		for _, filter := range s.include {
+			if filter.matches(p) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	for _, filter := range s.exclude {
+		if filter.matches(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// processDropped — число процессов, отброшенных пределом top_n и затем
+// пределом max_series.
+type processDropped struct {
+	topN      int
+	maxSeries int
+}
+
+// selectSeries отбирает процессы для метрик по PID: сортирует по потреблению,
+// применяет top-N и предел числа серий. Возвращает отобранные процессы и
+// количество отброшенных каждым пределом.
+func (s *processSelector) selectSeries(samples []processSample) ([]processSample, processDropped) {
+	sorted := append([]processSample{}, samples...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if s.topBy == "memory" {
+			return sorted[i].MemoryMB > sorted[j].MemoryMB
+		}
+		return sorted[i].CPU > sorted[j].CPU
+	})
+
+	var dropped processDropped
+	limit := len(sorted)
+	if s.topN > 0 && s.topN < limit {
+		dropped.topN = limit - s.topN
+		limit = s.topN
+	}
+	if s.maxSeries < limit {
+		dropped.maxSeries = limit - s.maxSeries
+		limit = s.maxSeries
+	}
+
+	return sorted[:limit], dropped
+}
+
+// selectGroups применяет предел числа серий к групповым метрикам по имени
+// процесса: при большом числе разных имён (например, одноразовых скриптов)
+// остаются группы с наибольшим суммарным потреблением. cpu и memory —
+// суммарная загрузка и память групп names. Возвращает отобранные имена и
+// количество отброшенных групп.
+func (s *processSelector) selectGroups(names []string, cpu, memory map[string]float64) (map[string]bool, int) {
+	usage := cpu
+	if s.topBy == "memory" {
+		usage = memory
+	}
+
+	names = append([]string{}, names...)
+	sort.Slice(names, func(i, j int) bool {
+		if usage[names[i]] != usage[names[j]] {
+			return usage[names[i]] > usage[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	limit := len(names)
+	if s.maxSeries < limit {
+		limit = s.maxSeries
+	}
+
+	selected := make(map[string]bool, limit)
+	for _, name := range names[:limit] {
+		selected[name] = true
+	}
+	return selected, len(names) - limit
+}
@@ -0,0 +1,384 @@
+package metrics
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shirou/gopsutil/process"
+
+	"node_exporter_custom/internal/deviceconfig"
+)
+
+// processGroupMatcher — скомпилированная группа из processes.groups.
+type processGroupMatcher struct {
+	name    string
+	exe     *regexp.Regexp
+	cmdline *regexp.Regexp
+	cgroup  *regexp.Regexp
+	user    *regexp.Regexp
+}
+
+type processGroupTotals struct {
+	processes   int
+	rssBytes    uint64
+	openFDs     int64
+	hasFDs      bool
+	threads     int64
+	oldestStart int64
+}
+
+// compileProcessGroups компилирует группы; группа без имени, без условий или с
+// некорректным выражением пропускается с записью в лог.
+func compileProcessGroups(groups []deviceconfig.ProcessGroup) []processGroupMatcher {
+	var result []processGroupMatcher
+	seen := make(map[string]bool)
+
+	for _, group := range groups {
+		if group.Name == "" {
+			log.Printf("process group without name skipped")
+			continue
+		}
+		if seen[group.Name] {
+			log.Printf("duplicate process group %q skipped", group.Name)
+			continue
+		}
+
+		matcher := processGroupMatcher{name: group.Name}
+		valid := true
+		for _, field := range []struct {
+			pattern string
+			target  **regexp.Regexp
+		}{
+			{group.Exe, &matcher.exe},
+			{group.Cmdline, &matcher.cmdline},
+			{group.Cgroup, &matcher.cgroup},
+			{group.User, &matcher.user},
+		} {
+			if field.pattern == "" {
+				continue
+			}
+			re, err := regexp.Compile(field.pattern)
+			if err != nil {
+				log.Printf("invalid pattern %q in process group %q: %v", field.pattern, group.Name, err)
+				valid = false
+				break
+			}
+			*field.target = re
+		}
+
+		if !valid {
+			continue
+		}
+		if matcher.exe == nil && matcher.cmdline == nil && matcher.cgroup == nil && matcher.user == nil {
+			log.Printf("process group %q has no match conditions, skipped", group.Name)
+			continue
+		}
+
+		seen[group.Name] = true
+		result = append(result, matcher)
+	}
+
+	return result
+}
+
+// processGroupCandidate лениво читает атрибуты процесса, нужные для
+// сопоставления: большинство процессов отсеивается по первому же условию.
+type processGroupCandidate struct {
+	proc *process.Process
+
+	names   []string
+	cmdline *string
+	cgroup  *string
+	user    *string
+}
+
+func (c *processGroupCandidate) exeNames() []string {
+	if c.names == nil {
+		c.names = []string{}
+		if name, err := c.proc.Name(); err == nil && name != "" {
+			c.names = append(c.names, name)
+		}
+		// путь к исполняемому файлу чужих процессов доступен только root
+		if exe, err := c.proc.Exe(); err == nil && exe != "" {
+			c.names = append(c.names, exe, filepath.Base(exe))
+		}
+	}
+	return c.names
+}
+
+func (c *processGroupCandidate) lazy(field **string, read func() string) string {
+	if *field == nil {
+		value := read()
+		*field = &value
+	}
+	return **field
+}
+
+func (m processGroupMatcher) matches(c *processGroupCandidate) bool {
+	if m.exe != nil {
+		matched := false
+		for _, name := range c.exeNames() {
+			if m.exe.MatchString(name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if m.cmdline != nil {
+		cmdline := c.lazy(&c.cmdline, func() string {
+			value, _ := c.proc.Cmdline()
+			return value
+		})
+		if !m.cmdline.MatchString(cmdline) {
+			return false
+		}
+	}
+	if m.cgroup != nil {
+		cgroup := c.lazy(&c.cgroup, func() string { return processCgroup(c.proc.Pid) })
+		if !m.cgroup.MatchString(cgroup) {
+			return false
+		}
+	}
+	if m.user != nil {
+		user := c.lazy(&c.user, func() string {
+			value, _ := c.proc.Username()
+			return value
+		})
+		if !m.user.MatchString(user) {
+			return false
+		}
+	}
+	return true
+}
+
+// RecordProcessGroups публикует агрегированные метрики именованных групп
+// процессов и состояние обязательных и запрещённых процессов. Процесс
+// учитывается в первой подходящей группе; списки наблюдения проверяются
+// независимо от групп.
+func RecordProcessGroups(cfg deviceconfig.ProcessesConfig) {
+	groups := compileProcessGroups(cfg.Groups)
+	required := compileProcessGroups(cfg.RequiredProcesses)
+	forbidden := compileProcessGroups(cfg.ForbiddenProcesses)
+	if len(groups) == 0 && len(required) == 0 && len(forbidden) == 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		counters := newCounterTracker()
+		var lastCycle time.Time
+		// члены групп прошлого цикла для подсчёта запусков и завершений
+		previousMembers := make(map[string]processGroupMembers, len(groups))
+
+		// счётчики публикуются с нуля, пока в группе нет процессов
+		for _, group := range groups {
+			labels := prometheus.Labels{"group": group.name}
+			NamedProcessGroupCPUSeconds.With(prometheus.Labels{"group": group.name, "mode": "user"}).Add(0)
+			NamedProcessGroupCPUSeconds.With(prometheus.Labels{"group": group.name, "mode": "system"}).Add(0)
+			NamedProcessGroupReadBytes.With(labels).Add(0)
+			NamedProcessGroupWriteBytes.With(labels).Add(0)
+			NamedProcessGroupStarts.With(labels).Add(0)
+			NamedProcessGroupExits.With(labels).Add(0)
+		}
+
+		for {
+			processes, err := process.Processes()
+			if err != nil {
+				log.Printf("failed to enumerate processes: %v", err)
+				<-ticker.C
+				continue
+			}
+
+			now := time.Now()
+			totals := make(map[string]*processGroupTotals, len(groups))
+			members := make(map[string]processGroupMembers, len(groups))
+			for _, group := range groups {
+				totals[group.name] = &processGroupTotals{}
+				members[group.name] = make(processGroupMembers)
+			}
+			requiredFound := make(map[string]bool, len(required))
+			forbiddenFound := make(map[string]bool, len(forbidden))
+
+			for _, proc := range processes {
+				candidate := &processGroupCandidate{proc: proc}
+				for _, group := range groups {
+					if !group.matches(candidate) {
+						continue
+					}
+					members[group.name][proc.Pid] = recordProcessGroupMember(group.name, proc, totals[group.name], counters, lastCycle, now)
+					break
+				}
+				matchProcessWatchlist(required, candidate, requiredFound)
+				matchProcessWatchlist(forbidden, candidate, forbiddenFound)
+			}
+
+			recordProcessWatchlist(ProcessRequiredUp, required, requiredFound)
+			recordProcessWatchlist(ProcessForbiddenPresent, forbidden, forbiddenFound)
+
+			for name, current := range members {
+				next, starts, exits := diffProcessGroupMembers(previousMembers[name], current)
+				// первый цикл задаёт исходный состав групп
+				if !lastCycle.IsZero() {
+					labels := prometheus.Labels{"group": name}
+					NamedProcessGroupStarts.With(labels).Add(float64(starts))
+					NamedProcessGroupExits.With(labels).Add(float64(exits))
+				}
+				previousMembers[name] = next
+			}
+
+			for name, total := range totals {
+				labels := prometheus.Labels{"group": name}
+				NamedProcessGroupProcesses.With(labels).Set(float64(total.processes))
+				if total.processes == 0 {
+					NamedProcessGroupResidentMemory.Delete(labels)
+					NamedProcessGroupOpenFDs.Delete(labels)
+					NamedProcessGroupThreads.Delete(labels)
+					NamedProcessGroupOldestStart.Delete(labels)
+					continue
+				}
+
+				NamedProcessGroupResidentMemory.With(labels).Set(float64(total.rssBytes))
+				NamedProcessGroupThreads.With(labels).Set(float64(total.threads))
+				if total.hasFDs {
+					NamedProcessGroupOpenFDs.With(labels).Set(float64(total.openFDs))
+				} else {
+					NamedProcessGroupOpenFDs.Delete(labels)
+				}
+				if total.oldestStart > 0 {
+					NamedProcessGroupOldestStart.With(labels).Set(float64(total.oldestStart) / 1000)
+				}
+			}
+
+			lastCycle = now
+			counters.Prune(now.Add(-time.Minute))
+			<-ticker.C
+		}
+	}()
+}
+
+// matchProcessWatchlist отмечает в found записи списка наблюдения, которым
+// соответствует процесс.
+func matchProcessWatchlist(watchlist []processGroupMatcher, candidate *processGroupCandidate, found map[string]bool) {
+	for _, entry := range watchlist {
+		if !found[entry.name] && entry.matches(candidate) {
+			found[entry.name] = true
+		}
+	}
+}
+
+// recordProcessWatchlist публикует для каждой записи списка наблюдения 1, если
+// подходящий процесс найден, и 0 в противном случае.
+func recordProcessWatchlist(gauge *prometheus.GaugeVec, watchlist []processGroupMatcher, found map[string]bool) {
+	for _, entry := range watchlist {
+		value := 0.0
+		if found[entry.name] {
+			value = 1
+		}
+		gauge.With(prometheus.Labels{"name": entry.name}).Set(value)
+	}
+}
+
+// processGroupMembers — состав группы за цикл: PID и время запуска процесса в
+// миллисекундах. 0 означает, что время запуска в этом цикле прочитать не
+// удалось.
+type processGroupMembers map[int32]int64
+
+// diffProcessGroupMembers считает запуски и завершения процессов группы между
+// двумя циклами и возвращает состав для сравнения в следующем цикле. Смена
+// времени запуска при том же PID — повторное использование PID: завершение и
+// запуск. Если время запуска не прочитано, процесс считается прежним и
+// сохраняет известное время, так что разовая ошибка чтения не выглядит как
+// перезапуск.
+func diffProcessGroupMembers(previous, current processGroupMembers) (next processGroupMembers, starts, exits int) {
+	next = make(processGroupMembers, len(current))
+	for pid, created := range current {
+		before, seen := previous[pid]
+		switch {
+		case !seen:
+			starts++
+		case created == 0:
+			created = before
+		case before != 0 && before != created:
+			exits++
+			starts++
+		}
+		next[pid] = created
+	}
+	for pid := range previous {
+		if _, ok := current[pid]; !ok {
+			exits++
+		}
+	}
+	return next, starts, exits
+}
+
+// recordProcessGroupMember добавляет процесс к группе и возвращает время его
+// запуска (0, если оно недоступно) для учёта запусков и завершений. Счётчики
+// процесса отслеживаются по PID и времени запуска: при повторном использовании
+// PID трекер видит смену identity. Процесс, запущенный после предыдущего цикла,
+// вносит все накопленные значения, а не только приращение со следующего цикла.
+func recordProcessGroupMember(group string, proc *process.Process, total *processGroupTotals, counters *counterTracker, lastCycle, now time.Time) int64 {
+	createTime, err := proc.CreateTime()
+	if err != nil {
+		// процесс завершился во время опроса или время запуска временно
+		// недоступно: ресурсы без identity не учитываются
+		return 0
+	}
+
+	total.processes++
+	if total.oldestStart == 0 || createTime < total.oldestStart {
+		total.oldestStart = createTime
+	}
+
+	identity := fmt.Sprintf("%d", createTime)
+	startedRecently := !lastCycle.IsZero() && createTime >= lastCycle.UnixMilli()
+	add := func(field string, value uint64) float64 {
+		observation := counters.Observe(fmt.Sprintf("%d|%s", proc.Pid, field), identity, value, counter64, now)
+		if !observation.Valid && startedRecently {
+			return float64(value)
+		}
+		return float64(observation.Delta)
+	}
+
+	if times, err := proc.Times(); err == nil {
+		// секунды CPU отслеживаются в миллисекундах, трекер работает с целыми
+		user := add("cpu_user", uint64(times.User*1000))
+		system := add("cpu_system", uint64(times.System*1000))
+		NamedProcessGroupCPUSeconds.With(prometheus.Labels{"group": group, "mode": "user"}).Add(user / 1000)
+		NamedProcessGroupCPUSeconds.With(prometheus.Labels{"group": group, "mode": "system"}).Add(system / 1000)
+	}
+
+	if memInfo, err := proc.MemoryInfo(); err == nil {
+		total.rssBytes += memInfo.RSS
+	}
+
+	if threads, err := proc.NumThreads(); err == nil {
+		total.threads += int64(threads)
+	}
+
+	if fds, err := proc.NumFDs(); err == nil {
+		total.openFDs += int64(fds)
+		total.hasFDs = true
+	}
+
+	if io, err := proc.IOCounters(); err == nil {
+		NamedProcessGroupReadBytes.With(prometheus.Labels{"group": group}).Add(add("read_bytes", io.ReadBytes))
+		NamedProcessGroupWriteBytes.With(prometheus.Labels{"group": group}).Add(add("write_bytes", io.WriteBytes))
+	}
+
+	if switches, err := proc.NumCtxSwitches(); err == nil {
+		NamedProcessGroupContextSwitches.With(prometheus.Labels{"group": group, "type": "voluntary"}).Add(add("ctx_voluntary", uint64(switches.Voluntary)))
+		NamedProcessGroupContextSwitches.With(prometheus.Labels{"group": group, "type": "involuntary"}).Add(add("ctx_involuntary", uint64(switches.Involuntary)))
+	}
+
+	return createTime
+}
@@ -0,0 +1,45 @@
+//go:build linux
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// processCgroup возвращает путь cgroup процесса: единой иерархии cgroup v2,
+// а для cgroup v1 — иерархии name=systemd или первой указанной.
+func processCgroup(pid int32) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return ""
+	}
+	return parseProcCgroup(string(data))
+}
+
+func parseProcCgroup(data string) string {
+	var systemd, first string
+
+	for _, line := range strings.Split(data, "\n") {
+		// формат строки: hierarchy-ID:controllers:path
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		if parts[1] == "name=systemd" {
+			systemd = parts[2]
+		}
+		if first == "" {
+			first = parts[2]
+		}
+	}
+
+	if systemd != "" {
+		return systemd
+	}
+	return first
+}
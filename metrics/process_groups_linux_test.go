@@ -0,0 +1,54 @@
+//go:build linux
+
+package metrics
+
+import "testing"
+
+func TestParseProcCgroup(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "cgroup v2",
+			data: "0::/system.slice/nginx.service\n",
+			want: "/system.slice/nginx.service",
+		},
+		{
+			name: "hybrid prefers unified hierarchy",
+			data: "12:cpu,cpuacct:/system.slice/nginx.service\n" +
+				"1:name=systemd:/system.slice/nginx.service\n" +
+				"0::/system.slice/nginx.service\n",
+			want: "/system.slice/nginx.service",
+		},
+		{
+			name: "cgroup v1 prefers name=systemd",
+			data: "11:memory:/docker/3f4e\n" +
+				"4:cpu,cpuacct:/docker/3f4e\n" +
+				"1:name=systemd:/system.slice/docker-3f4e.scope\n",
+			want: "/system.slice/docker-3f4e.scope",
+		},
+		{
+			name: "cgroup v1 without systemd hierarchy",
+			data: "5:memory:/user/1000.user\n4:cpu:/user/1000.user/c2.session\n",
+			want: "/user/1000.user",
+		},
+		{
+			name: "path with colon",
+			data: "0::/kubepods/pod1:container\n",
+			want: "/kubepods/pod1:container",
+		},
+		{
+			name: "empty",
+			data: "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := parseProcCgroup(tt.data); got != tt.want {
+			t.Errorf("%s: parseProcCgroup() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"node_exporter_custom/internal/deviceconfig"
+)
+
+func TestCompileProcessGroups(t *testing.T) {
+	groups := compileProcessGroups([]deviceconfig.ProcessGroup{
+		{Name: "nginx", Exe: "^nginx$"},
+		{Name: "", Exe: "^postgres$"},
+		{Name: "nginx", Exe: "^nginx-debug$"},
+		{Name: "empty"},
+		{Name: "broken", Exe: "^java$", Cmdline: "(unclosed"},
+		{Name: "app", Cmdline: "--config=/etc/app", Cgroup: "app\\.service", User: "^app$"},
+	})
+
+	var names []string
+	for _, group := range groups {
+		names = append(names, group.name)
+	}
+	// без имени, повторное имя, без условий и с ошибкой в выражении пропускаются
+	if want := []string{"nginx", "app"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("compiled groups = %v, want %v", names, want)
+	}
+
+	if groups[0].exe == nil || groups[0].cmdline != nil || groups[0].cgroup != nil || groups[0].user != nil {
+		t.Errorf("nginx: unexpected compiled conditions %+v", groups[0])
+	}
+	if groups[1].exe != nil || groups[1].cmdline == nil || groups[1].cgroup == nil || groups[1].user == nil {
+		t.Errorf("app: unexpected compiled conditions %+v", groups[1])
+	}
+}
+
+// testProcessCandidate возвращает кандидата с уже прочитанными атрибутами, чтобы
+// сопоставление не обращалось к процессу.
+func testProcessCandidate(names []string, cmdline, cgroup, user string) *processGroupCandidate {
+	return &processGroupCandidate{names: names, cmdline: &cmdline, cgroup: &cgroup, user: &user}
+}
+
+func TestProcessGroupMatches(t *testing.T) {
+	groups := compileProcessGroups([]deviceconfig.ProcessGroup{
+		{Name: "nginx", Exe: "^nginx$"},
+		{Name: "java-app", Exe: "^/opt/jdk/bin/java$", Cmdline: "-jar billing\\.jar"},
+		{Name: "units", Cgroup: "^/system\\.slice/app-.*\\.service$", User: "^app$"},
+	})
+	matchers := make(map[string]processGroupMatcher)
+	for _, group := range groups {
+		matchers[group.name] = group
+	}
+
+	tests := []struct {
+		name      string
+		group     string
+		candidate *processGroupCandidate
+		want      bool
+	}{
+		{
+			name:      "exe by process name",
+			group:     "nginx",
+			candidate: testProcessCandidate([]string{"nginx"}, "nginx: worker process", "/system.slice/nginx.service", "www-data"),
+			want:      true,
+		},
+		{
+			name:      "exe by base name of path",
+			group:     "nginx",
+			candidate: testProcessCandidate([]string{"nginx: worker", "/usr/sbin/nginx", "nginx"}, "", "", ""),
+			want:      true,
+		},
+		{
+			name:      "exe mismatch",
+			group:     "nginx",
+			candidate: testProcessCandidate([]string{"nginx-debug"}, "", "", ""),
+			want:      false,
+		},
+		{
+			name:      "exe path and cmdline",
+			group:     "java-app",
+			candidate: testProcessCandidate([]string{"java", "/opt/jdk/bin/java", "java"}, "/opt/jdk/bin/java -Xmx2g -jar billing.jar", "", "billing"),
+			want:      true,
+		},
+		{
+			name:      "all conditions must match",
+			group:     "java-app",
+			candidate: testProcessCandidate([]string{"java", "/opt/jdk/bin/java", "java"}, "/opt/jdk/bin/java -jar reports.jar", "", "billing"),
+			want:      false,
+		},
+		{
+			// путь исполняемого файла чужих процессов без root недоступен
+			name:      "exe path unavailable",
+			group:     "java-app",
+			candidate: testProcessCandidate([]string{"java"}, "java -jar billing.jar", "", ""),
+			want:      false,
+		},
+		{
+			name:      "cgroup and user",
+			group:     "units",
+			candidate: testProcessCandidate([]string{"worker"}, "", "/system.slice/app-worker.service", "app"),
+			want:      true,
+		},
+		{
+			name:      "cgroup matches, user differs",
+			group:     "units",
+			candidate: testProcessCandidate([]string{"worker"}, "", "/system.slice/app-worker.service", "root"),
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := matchers[tt.group].matches(tt.candidate); got != tt.want {
+			t.Errorf("%s: matches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiffProcessGroupMembers(t *testing.T) {
+	tests := []struct {
+		name       string
+		previous   processGroupMembers
+		current    processGroupMembers
+		wantNext   processGroupMembers
+		wantStarts int
+		wantExits  int
+	}{
+		{
+			name:       "first cycle",
+			current:    processGroupMembers{100: 1000, 200: 2000},
+			wantNext:   processGroupMembers{100: 1000, 200: 2000},
+			wantStarts: 2,
+		},
+		{
+			name:     "unchanged",
+			previous: processGroupMembers{100: 1000, 200: 2000},
+			current:  processGroupMembers{100: 1000, 200: 2000},
+			wantNext: processGroupMembers{100: 1000, 200: 2000},
+		},
+		{
+			name:       "start and exit",
+			previous:   processGroupMembers{100: 1000, 200: 2000},
+			current:    processGroupMembers{100: 1000, 300: 3000},
+			wantNext:   processGroupMembers{100: 1000, 300: 3000},
+			wantStarts: 1,
+			wantExits:  1,
+		},
+		{
+			name:       "PID reuse",
+			previous:   processGroupMembers{100: 1000},
+			current:    processGroupMembers{100: 5000},
+			wantNext:   processGroupMembers{100: 5000},
+			wantStarts: 1,
+			wantExits:  1,
+		},
+		{
+			// время запуска не прочитано: процесс прежний, время сохраняется
+			name:     "create time unavailable",
+			previous: processGroupMembers{100: 1000},
+			current:  processGroupMembers{100: 0},
+			wantNext: processGroupMembers{100: 1000},
+		},
+		{
+			name:     "create time available again",
+			previous: processGroupMembers{100: 1000},
+			current:  processGroupMembers{100: 1000},
+			wantNext: processGroupMembers{100: 1000},
+		},
+		{
+			// новый процесс без времени запуска не учитывается повторно,
+			// когда время становится доступно
+			name:     "late create time",
+			previous: processGroupMembers{100: 0},
+			current:  processGroupMembers{100: 1000},
+			wantNext: processGroupMembers{100: 1000},
+		},
+		{
+			name:      "group emptied",
+			previous:  processGroupMembers{100: 1000, 200: 0},
+			current:   processGroupMembers{},
+			wantNext:  processGroupMembers{},
+			wantExits: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, starts, exits := diffProcessGroupMembers(tt.previous, tt.current)
+			if !reflect.DeepEqual(next, tt.wantNext) {
+				t.Errorf("next = %v, want %v", next, tt.wantNext)
+			}
+			if starts != tt.wantStarts || exits != tt.wantExits {
+				t.Errorf("starts, exits = %d, %d, want %d, %d", starts, exits, tt.wantStarts, tt.wantExits)
+			}
+		})
+	}
+}
+
+func TestRecordProcessWatchlist(t *testing.T) {
+	ProcessRequiredUp.Reset()
+	ProcessForbiddenPresent.Reset()
+
+	required := compileProcessGroups([]deviceconfig.ProcessGroup{
+		{Name: "sshd", Exe: "^sshd$"},
+		{Name: "chronyd", Exe: "^chronyd$"},
+	})
+	forbidden := compileProcessGroups([]deviceconfig.ProcessGroup{
+		{Name: "telnetd", Exe: "^in\\.telnetd$"},
+		{Name: "miner", Cmdline: "stratum\\+tcp://"},
+	})
+
+	candidates := []*processGroupCandidate{
+		testProcessCandidate([]string{"sshd"}, "/usr/sbin/sshd -D", "", "root"),
+		testProcessCandidate([]string{"xmrig"}, "xmrig -o stratum+tcp://pool:3333", "", "nobody"),
+	}
+	requiredFound := make(map[string]bool)
+	forbiddenFound := make(map[string]bool)
+	for _, candidate := range candidates {
+		matchProcessWatchlist(required, candidate, requiredFound)
+		matchProcessWatchlist(forbidden, candidate, forbiddenFound)
+	}
+	recordProcessWatchlist(ProcessRequiredUp, required, requiredFound)
+	recordProcessWatchlist(ProcessForbiddenPresent, forbidden, forbiddenFound)
+
+	want := []string{
+		"process_forbidden_present{name=miner} 1",
+		"process_forbidden_present{name=telnetd} 0",
+		"process_required_up{name=chronyd} 0",
+		"process_required_up{name=sshd} 1",
+	}
+	if got := gatherSeries(t, ProcessRequiredUp, ProcessForbiddenPresent); !reflect.DeepEqual(got, want) {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package metrics
+
+// processCgroup: в Windows cgroup нет, условие cgroup в группе процессов
+// совпадает только с пустой строкой.
+func processCgroup(pid int32) string {
+	return ""
+}
@@ -58,3 +58,139 @@ var (
 		[]string{"process", "instances"},
 	)
 )
+
+// ProcessPidCPUSeconds — накопленное процессорное время по PID. Имя
+// process_cpu_seconds_total занято стандартной метрикой самого агента.
+var ProcessPidCPUSeconds = newConstCounterVec(
+	"process_pid_cpu_seconds_total",
+	"Total CPU time consumed by each active process since it started in seconds",
+	[]string{"process", "pid", "mode"},
+)
+
+var ProcessSeriesDropped = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "process_series_dropped",
+		Help: "Number of processes whose per-PID metrics were not emitted in the last cycle (reason = top_n/max_series)",
+	},
+	[]string{"reason"},
+)
+
+var ProcessGroupSeriesDropped = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "process_group_series_dropped",
+		Help: "Number of process names whose aggregated metrics were not emitted in the last cycle because of max_series limit",
+	},
+)
+
+// Метрики именованных групп процессов из processes.groups. Счётчики
+// накапливают приращения отдельных процессов и не уменьшаются при их
+// завершении, поэтому переживают перезапуски и смену PID.
+var (
+	NamedProcessGroupProcesses = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "named_process_group_processes",
+			Help: "Number of processes in a configured process group",
+		},
+		[]string{"group"},
+	)
+
+	NamedProcessGroupCPUSeconds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "named_process_group_cpu_seconds_total",
+			Help: "CPU time consumed by processes of a configured process group in seconds",
+		},
+		[]string{"group", "mode"},
+	)
+
+	NamedProcessGroupResidentMemory = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "named_process_group_resident_memory_bytes",
+			Help: "Total resident memory of processes in a configured process group in bytes",
+		},
+		[]string{"group"},
+	)
+
+	NamedProcessGroupOpenFDs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "named_process_group_open_fds",
+			Help: "Number of open file descriptors of processes in a configured process group",
+		},
+		[]string{"group"},
+	)
+
+	NamedProcessGroupThreads = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "named_process_group_threads",
+			Help: "Number of threads of processes in a configured process group",
+		},
+		[]string{"group"},
+	)
+
+	NamedProcessGroupReadBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "named_process_group_read_bytes_total",
+			Help: "Bytes read by processes of a configured process group",
+		},
+		[]string{"group"},
+	)
+
+	NamedProcessGroupWriteBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "named_process_group_write_bytes_total",
+			Help: "Bytes written by processes of a configured process group",
+		},
+		[]string{"group"},
+	)
+
+	NamedProcessGroupContextSwitches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "named_process_group_context_switches_total",
+			Help: "Context switches of processes in a configured process group",
+		},
+		[]string{"group", "type"},
+	)
+
+	NamedProcessGroupStarts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "named_process_group_starts_total",
+			Help: "Number of process starts observed in a configured process group",
+		},
+		[]string{"group"},
+	)
+
+	NamedProcessGroupExits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "named_process_group_exits_total",
+			Help: "Number of process exits observed in a configured process group",
+		},
+		[]string{"group"},
+	)
+
+	NamedProcessGroupOldestStart = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "named_process_group_oldest_start_time_seconds",
+			Help: "Start time of the oldest process in a configured process group, in seconds since the Unix epoch",
+		},
+		[]string{"group"},
+	)
+)
+
+// Наблюдение за обязательными и запрещёнными процессами из
+// processes.required_processes и processes.forbidden_processes.
+var (
+	ProcessRequiredUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "process_required_up",
+			Help: "Whether a required process is running (1 = running, 0 = not found)",
+		},
+		[]string{"name"},
+	)
+
+	ProcessForbiddenPresent = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "process_forbidden_present",
+			Help: "Whether a forbidden process is running (1 = found, 0 = not found)",
+		},
+		[]string{"name"},
+	)
+)
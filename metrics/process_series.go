@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// processTotals — суммарное потребление всех экземпляров процесса с одним
+// именем.
+type processTotals struct {
+	count      int
+	rssMB      float64
+	privateMB  float64
+	cpuPercent float64
+}
+
+// processSeries — серии метрик процессов по PID и по имени. Серии
+// завершившихся и отброшенных процессов, а также прежние значения лейбла
+// instances удаляются при Flush, остальные обновляются на месте.
+type processSeries struct {
+	memory          *gaugeSeries
+	cpu             *gaugeSeries
+	instances       *gaugeSeries
+	groupWorkingSet *gaugeSeries
+	groupPrivate    *gaugeSeries
+	groupCPU        *gaugeSeries
+}
+
+func newProcessSeries() *processSeries {
+	return &processSeries{
+		memory:          newGaugeSeries(ProccessMemoryUsage),
+		cpu:             newGaugeSeries(ProccessCPUUsage),
+		instances:       newGaugeSeries(ProcessInstanceCount),
+		groupWorkingSet: newGaugeSeries(ProcessGroupMemoryWorkingSet),
+		groupPrivate:    newGaugeSeries(ProcessGroupMemoryPrivate),
+		groupCPU:        newGaugeSeries(ProcessGroupCPUUsage),
+	}
+}
+
+// recordProcesses публикует метрики по PID для процессов, отобранных
+// selector, и возвращает отобранные процессы.
+func (s *processSeries) recordProcesses(selector *processSelector, samples []processSample) []processSample {
+	selected, dropped := selector.selectSeries(samples)
+	for _, sample := range selected {
+		labels := prometheus.Labels{"process": sample.Name, "pid": sample.PID}
+		s.memory.Set(labels, sample.MemoryMB)
+		s.cpu.Set(labels, sample.CPU)
+	}
+	s.memory.Flush()
+	s.cpu.Flush()
+
+	ProcessSeriesDropped.With(prometheus.Labels{"reason": "top_n"}).Set(float64(dropped.topN))
+	ProcessSeriesDropped.With(prometheus.Labels{"reason": "max_series"}).Set(float64(dropped.maxSeries))
+	return selected
+}
+
+// recordGroups публикует суммарные метрики по имени процесса; имена сверх
+// max_series не публикуются.
+func (s *processSeries) recordGroups(selector *processSelector, totals map[string]processTotals) {
+	names := make([]string, 0, len(totals))
+	cpu := make(map[string]float64, len(totals))
+	memory := make(map[string]float64, len(totals))
+	for name, total := range totals {
+		names = append(names, name)
+		cpu[name] = total.cpuPercent
+		memory[name] = total.rssMB
+	}
+
+	groups, dropped := selector.selectGroups(names, cpu, memory)
+	ProcessGroupSeriesDropped.Set(float64(dropped))
+
+	for name, total := range totals {
+		if !groups[name] {
+			continue
+		}
+		labels := prometheus.Labels{"process": name, "instances": fmt.Sprint(total.count)}
+		s.instances.Set(prometheus.Labels{"process": name}, float64(total.count))
+		s.groupWorkingSet.Set(labels, total.rssMB)
+		s.groupPrivate.Set(labels, total.privateMB)
+		s.groupCPU.Set(labels, total.cpuPercent)
+	}
+
+	s.instances.Flush()
+	s.groupWorkingSet.Flush()
+	s.groupPrivate.Flush()
+	s.groupCPU.Flush()
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"node_exporter_custom/internal/deviceconfig"
+)
+
+func TestSelectSeriesDropReasons(t *testing.T) {
+	samples := []processSample{
+		{PID: "1", CPU: 5}, {PID: "2", CPU: 40}, {PID: "3", CPU: 1}, {PID: "4", CPU: 20}, {PID: "5", CPU: 0},
+	}
+
+	tests := []struct {
+		cfg     deviceconfig.ProcessesConfig
+		pids    []string
+		dropped processDropped
+	}{
+		{deviceconfig.ProcessesConfig{}, []string{"2", "4", "1", "3", "5"}, processDropped{}},
+		{deviceconfig.ProcessesConfig{TopN: 3}, []string{"2", "4", "1"}, processDropped{topN: 2}},
+		{deviceconfig.ProcessesConfig{MaxSeries: 2}, []string{"2", "4"}, processDropped{maxSeries: 3}},
+		// max_series применяется к тому, что осталось после top_n
+		{deviceconfig.ProcessesConfig{TopN: 4, MaxSeries: 2}, []string{"2", "4"}, processDropped{topN: 1, maxSeries: 2}},
+		{deviceconfig.ProcessesConfig{TopN: 2, MaxSeries: 3}, []string{"2", "4"}, processDropped{topN: 3}},
+	}
+
+	for _, tt := range tests {
+		selected, dropped := newProcessSelector(tt.cfg).selectSeries(samples)
+		var pids []string
+		for _, sample := range selected {
+			pids = append(pids, sample.PID)
+		}
+		if !reflect.DeepEqual(pids, tt.pids) || dropped != tt.dropped {
+			t.Errorf("%+v: selectSeries() = %v, %+v; want %v, %+v", tt.cfg, pids, dropped, tt.pids, tt.dropped)
+		}
+	}
+}
+
+func TestProcessSeries(t *testing.T) {
+	ProccessMemoryUsage.Reset()
+	ProccessCPUUsage.Reset()
+	ProcessInstanceCount.Reset()
+	ProcessGroupMemoryWorkingSet.Reset()
+	ProcessGroupMemoryPrivate.Reset()
+	ProcessGroupCPUUsage.Reset()
+	ProcessSeriesDropped.Reset()
+
+	gather := func() []string {
+		return gatherSeries(t, ProccessMemoryUsage, ProccessCPUUsage, ProcessInstanceCount, ProcessGroupCPUUsage, ProcessSeriesDropped)
+	}
+
+	selector := newProcessSelector(deviceconfig.ProcessesConfig{TopN: 2})
+	series := newProcessSeries()
+
+	series.recordProcesses(selector, []processSample{
+		{PID: "100", Name: "nginx", CPU: 10, MemoryMB: 50},
+		{PID: "101", Name: "nginx", CPU: 5, MemoryMB: 40},
+		{PID: "200", Name: "sshd", CPU: 1, MemoryMB: 8},
+	})
+	series.recordGroups(selector, map[string]processTotals{
+		"nginx": {count: 2, rssMB: 90, cpuPercent: 15},
+		"sshd":  {count: 1, rssMB: 8, cpuPercent: 1},
+	})
+	want := []string{
+		"active_proccess_memory_usage{pid=100,process=nginx} 50",
+		"active_proccess_memory_usage{pid=101,process=nginx} 40",
+		"proccess_cpu_usage_percent{pid=100,process=nginx} 10",
+		"proccess_cpu_usage_percent{pid=101,process=nginx} 5",
+		"process_group_cpu_usage_percent{instances=1,process=sshd} 1",
+		"process_group_cpu_usage_percent{instances=2,process=nginx} 15",
+		"process_instance_count{process=nginx} 2",
+		"process_instance_count{process=sshd} 1",
+		"process_series_dropped{reason=max_series} 0",
+		"process_series_dropped{reason=top_n} 1",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("first cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// nginx 101 завершился, sshd вошёл в top-N: серии 101 и прежнее значение
+	// instances удаляются
+	series.recordProcesses(selector, []processSample{
+		{PID: "100", Name: "nginx", CPU: 12, MemoryMB: 52},
+		{PID: "200", Name: "sshd", CPU: 3, MemoryMB: 8},
+	})
+	series.recordGroups(selector, map[string]processTotals{
+		"nginx": {count: 1, rssMB: 52, cpuPercent: 12},
+		"sshd":  {count: 1, rssMB: 8, cpuPercent: 3},
+	})
+	want = []string{
+		"active_proccess_memory_usage{pid=100,process=nginx} 52",
+		"active_proccess_memory_usage{pid=200,process=sshd} 8",
+		"proccess_cpu_usage_percent{pid=100,process=nginx} 12",
+		"proccess_cpu_usage_percent{pid=200,process=sshd} 3",
+		"process_group_cpu_usage_percent{instances=1,process=nginx} 12",
+		"process_group_cpu_usage_percent{instances=1,process=sshd} 3",
+		"process_instance_count{process=nginx} 1",
+		"process_instance_count{process=sshd} 1",
+		"process_series_dropped{reason=max_series} 0",
+		"process_series_dropped{reason=top_n} 0",
+	}
+	if got := gather(); !reflect.DeepEqual(got, want) {
+		t.Errorf("second cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
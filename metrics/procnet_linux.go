@@ -0,0 +1,165 @@
+//go:build linux
+
+package metrics
+
+import (
+	"encoding/hex"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// tcpStates — коды состояний из include/net/tcp_states.h.
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+	"0C": "NEW_SYN_RECV",
+}
+
+type procNetSocket struct {
+	LocalIP    net.IP
+	LocalPort  uint16
+	RemoteIP   net.IP
+	RemotePort uint16
+	State      string
+	UID        string
+	Inode      string
+}
+
+// parseProcNetSockets разбирает таблицы /proc/net/{tcp,tcp6,udp,udp6}.
+func parseProcNetSockets(data string) []procNetSocket {
+	var sockets []procNetSocket
+
+	for i, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 10 {
+			continue
+		}
+
+		localIP, localPort, ok := parseProcNetAddress(fields[1])
+		if !ok {
+			continue
+		}
+		remoteIP, remotePort, ok := parseProcNetAddress(fields[2])
+		if !ok {
+			continue
+		}
+
+		state := tcpStates[strings.ToUpper(fields[3])]
+		if state == "" {
+			state = "UNKNOWN"
+		}
+
+		sockets = append(sockets, procNetSocket{
+			LocalIP:    localIP,
+			LocalPort:  localPort,
+			RemoteIP:   remoteIP,
+			RemotePort: remotePort,
+			State:      state,
+			UID:        fields[7],
+			Inode:      fields[9],
+		})
+	}
+
+	return sockets
+}
+
+// parseProcNetAddress разбирает адрес вида "0100007F:0035". IPv4 записан как
+// одно 32-битное слово, IPv6 — как четыре слова, каждое в порядке байт хоста.
+func parseProcNetAddress(value string) (net.IP, uint16, bool) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return nil, 0, false
+	}
+
+	raw, err := hex.DecodeString(parts[0])
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, 0, false
+	}
+
+	ip := make(net.IP, len(raw))
+	for word := 0; word < len(raw); word += 4 {
+		ip[word], ip[word+1], ip[word+2], ip[word+3] = raw[word+3], raw[word+2], raw[word+1], raw[word]
+	}
+
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	return ip, uint16(port), true
+}
+
+// parseProcNetSNMP разбирает пары строк заголовок/значения из /proc/net/snmp и
+// /proc/net/netstat: "Tcp: ActiveOpens ..." / "Tcp: 12 ...".
+func parseProcNetSNMP(data string) map[string]map[string]int64 {
+	result := make(map[string]map[string]int64)
+	lines := strings.Split(strings.TrimSpace(data), "\n")
+
+	for i := 0; i+1 < len(lines); i += 2 {
+		header := strings.Fields(lines[i])
+		values := strings.Fields(lines[i+1])
+		if len(header) == 0 || len(header) != len(values) || header[0] != values[0] {
+			continue
+		}
+
+		proto := strings.TrimSuffix(header[0], ":")
+		if result[proto] == nil {
+			result[proto] = make(map[string]int64)
+		}
+		for j := 1; j < len(header); j++ {
+			if value, err := strconv.ParseInt(values[j], 10, 64); err == nil {
+				result[proto][header[j]] = value
+			}
+		}
+	}
+
+	return result
+}
+
+// parseProcNetSNMP6 разбирает /proc/net/snmp6: "Udp6InDatagrams  123".
+func parseProcNetSNMP6(data string) map[string]int64 {
+	result := make(map[string]int64)
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			result[fields[0]] = value
+		}
+	}
+	return result
+}
+
+// parseProcNetSockstat разбирает /proc/net/sockstat{,6}:
+// "TCP: inuse 5 orphan 0 tw 2 alloc 7 mem 1".
+func parseProcNetSockstat(data string) map[string]map[string]int64 {
+	result := make(map[string]map[string]int64)
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || len(fields)%2 != 1 {
+			continue
+		}
+
+		proto := strings.ToLower(strings.TrimSuffix(fields[0], ":"))
+		if result[proto] == nil {
+			result[proto] = make(map[string]int64)
+		}
+		for j := 1; j+1 < len(fields); j += 2 {
+			if value, err := strconv.ParseInt(fields[j+1], 10, 64); err == nil {
+				result[proto][fields[j]] = value
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+//go:build linux
+
+package metrics
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Файлы testdata/procnet/* — сокращённые /proc/net/{snmp,netstat,snmp6,
+// sockstat,sockstat6,tcp,tcp6,udp} хоста с sshd, systemd-resolved и
+// WireGuard на 51820/udp.
+
+func readProcNetFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "procnet", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseProcNetSNMP(t *testing.T) {
+	snmp := parseProcNetSNMP(readProcNetFixture(t, "snmp"))
+
+	for proto, fields := range map[string]map[string]int64{
+		"Tcp": {"ActiveOpens": 48213, "RetransSegs": 4120, "MaxConn": -1, "InCsumErrors": 0},
+		"Udp": {"InDatagrams": 93210, "NoPorts": 212, "RcvbufErrors": 3},
+		"Ip":  {"Forwarding": 1, "OutTransmits": 1502870},
+	} {
+		for field, want := range fields {
+			if got, ok := snmp[proto][field]; !ok || got != want {
+				t.Errorf("snmp[%s][%s] = %d (present %v), want %d", proto, field, got, ok, want)
+			}
+		}
+	}
+	if len(snmp["Icmp"]) != 29 || len(snmp["IcmpMsg"]) != 4 {
+		t.Errorf("Icmp fields = %d, IcmpMsg fields = %d, want 29 and 4", len(snmp["Icmp"]), len(snmp["IcmpMsg"]))
+	}
+
+	netstat := parseProcNetSNMP(readProcNetFixture(t, "netstat"))
+	if got := netstat["TcpExt"]["ListenOverflows"]; got != 17 {
+		t.Errorf("TcpExt ListenOverflows = %d, want 17", got)
+	}
+	if got := netstat["IpExt"]["InOctets"]; got != 2409118213 {
+		t.Errorf("IpExt InOctets = %d, want 2409118213", got)
+	}
+}
+
+func TestParseProcNetSNMPMismatchedPair(t *testing.T) {
+	// пара с разным числом полей пропускается, следующие разбираются
+	data := "Tcp: ActiveOpens PassiveOpens\nTcp: 1\nUdp: InDatagrams\nUdp: 5\n"
+	want := map[string]map[string]int64{"Udp": {"InDatagrams": 5}}
+	if got := parseProcNetSNMP(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProcNetSNMP() = %v, want %v", got, want)
+	}
+}
+
+func TestParseProcNetSNMP6(t *testing.T) {
+	got := parseProcNetSNMP6(readProcNetFixture(t, "snmp6"))
+	if len(got) != 17 {
+		t.Errorf("parsed %d fields, want 17", len(got))
+	}
+	if got["Udp6InDatagrams"] != 4211 || got["Udp6NoPorts"] != 9 {
+		t.Errorf("Udp6InDatagrams = %d, Udp6NoPorts = %d, want 4211 and 9", got["Udp6InDatagrams"], got["Udp6NoPorts"])
+	}
+}
+
+func TestParseProcNetSockstat(t *testing.T) {
+	got := parseProcNetSockstat(readProcNetFixture(t, "sockstat"))
+	want := map[string]map[string]int64{
+		"sockets": {"used": 812},
+		"tcp":     {"inuse": 31, "orphan": 0, "tw": 14, "alloc": 44, "mem": 9},
+		"udp":     {"inuse": 12, "mem": 6},
+		"udplite": {"inuse": 0},
+		"raw":     {"inuse": 1},
+		"frag":    {"inuse": 0, "memory": 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sockstat = %v, want %v", got, want)
+	}
+
+	got = parseProcNetSockstat(readProcNetFixture(t, "sockstat6"))
+	if got["tcp6"]["inuse"] != 9 || got["udp6"]["inuse"] != 5 {
+		t.Errorf("sockstat6 = %v", got)
+	}
+}
+
+func TestParseProcNetSockets(t *testing.T) {
+	tests := []struct {
+		name string
+		want []procNetSocket
+	}{
+		{"tcp", []procNetSocket{
+			{LocalIP: net.IPv4(127, 0, 0, 1), LocalPort: 631, RemoteIP: net.IPv4zero, State: "LISTEN", UID: "0", Inode: "23811"},
+			{LocalIP: net.IPv4zero, LocalPort: 22, RemoteIP: net.IPv4zero, State: "LISTEN", UID: "0", Inode: "21035"},
+			{LocalIP: net.IPv4(192, 168, 2, 15), LocalPort: 22, RemoteIP: net.IPv4(192, 168, 2, 100), RemotePort: 54004, State: "ESTABLISHED", UID: "0", Inode: "98123"},
+			{LocalIP: net.IPv4(192, 168, 2, 15), LocalPort: 35390, RemoteIP: net.IPv4(52, 185, 23, 34), RemotePort: 443, State: "TIME_WAIT", UID: "0", Inode: "0"},
+		}},
+		// IPv6 записан четырьмя словами в порядке байт хоста
+		{"tcp6", []procNetSocket{
+			{LocalIP: net.IPv6unspecified, LocalPort: 22, RemoteIP: net.IPv6unspecified, State: "LISTEN", UID: "0", Inode: "21037"},
+			{LocalIP: net.IPv6loopback, LocalPort: 631, RemoteIP: net.IPv6unspecified, State: "LISTEN", UID: "0", Inode: "23810"},
+			{LocalIP: net.ParseIP("::ffff:192.168.2.15"), LocalPort: 8080, RemoteIP: net.ParseIP("::ffff:192.168.2.100"), RemotePort: 54026, State: "ESTABLISHED", UID: "1000", Inode: "99410"},
+		}},
+		{"udp", []procNetSocket{
+			{LocalIP: net.IPv4(127, 0, 0, 53), LocalPort: 53, RemoteIP: net.IPv4zero, State: "CLOSE", UID: "991", Inode: "20512"},
+			{LocalIP: net.IPv4zero, LocalPort: 51820, RemoteIP: net.IPv4zero, State: "CLOSE", UID: "0", Inode: "31644"},
+			{LocalIP: net.IPv4(192, 168, 2, 15), LocalPort: 57778, RemoteIP: net.IPv4(192, 168, 2, 1), RemotePort: 53, State: "ESTABLISHED", UID: "1000", Inode: "99873"},
+		}},
+	}
+
+	for _, tt := range tests {
+		got := parseProcNetSockets(readProcNetFixture(t, tt.name))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: parsed %d sockets, want %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			g, w := got[i], tt.want[i]
+			if !g.LocalIP.Equal(w.LocalIP) || !g.RemoteIP.Equal(w.RemoteIP) ||
+				g.LocalPort != w.LocalPort || g.RemotePort != w.RemotePort ||
+				g.State != w.State || g.UID != w.UID || g.Inode != w.Inode {
+				t.Errorf("%s[%d] = %+v, want %+v", tt.name, i, g, w)
+			}
+		}
+	}
+}
+
+func TestParseProcNetAddressMalformed(t *testing.T) {
+	for _, value := range []string{"", "0100007F", "0100007F:zz", "00007F:0035", "XX00007F:0035", "0100007F:10000"} {
+		if _, _, ok := parseProcNetAddress(value); ok {
+			t.Errorf("parseProcNetAddress(%q) succeeded", value)
+		}
+	}
+}
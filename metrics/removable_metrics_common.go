@@ -0,0 +1,21 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	RemovableDeviceInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "removable_device_info",
+			Help: "Attached removable block device (USB mass storage, card readers, optical drives)",
+		},
+		[]string{"device", "vendor", "model", "serial", "bus"},
+	)
+
+	RemovableDeviceEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "removable_device_events_total",
+			Help: "Number of removable device attach/detach events observed since agent start",
+		},
+		[]string{"action", "bus"},
+	)
+)
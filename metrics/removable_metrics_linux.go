@@ -0,0 +1,175 @@
+//go:build linux
+
+package metrics
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type removableDevice struct {
+	Device string
+	Vendor string
+	Model  string
+	Serial string
+	Bus    string
+}
+
+func (d removableDevice) key() string {
+	return d.Device + "|" + d.Serial
+}
+
+func RecordRemovableDevices() {
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+
+		var known map[string]removableDevice
+		infoSeries := newGaugeSeries(RemovableDeviceInfo)
+
+		for {
+			current := discoverRemovableDevices("/sys/block")
+
+			if known != nil {
+				for key, dev := range current {
+					if _, ok := known[key]; !ok {
+						log.Printf("removable device attached: %s (vendor=%s, model=%s, serial=%s, bus=%s)",
+							dev.Device, dev.Vendor, dev.Model, dev.Serial, dev.Bus)
+						RemovableDeviceEvents.With(prometheus.Labels{"action": "attach", "bus": dev.Bus}).Inc()
+					}
+				}
+				for key, dev := range known {
+					if _, ok := current[key]; !ok {
+						log.Printf("removable device detached: %s (serial=%s, bus=%s)", dev.Device, dev.Serial, dev.Bus)
+						RemovableDeviceEvents.With(prometheus.Labels{"action": "detach", "bus": dev.Bus}).Inc()
+					}
+				}
+			} else {
+				for _, action := range []string{"attach", "detach"} {
+					for _, bus := range []string{"usb", "mmc"} {
+						RemovableDeviceEvents.With(prometheus.Labels{"action": action, "bus": bus}).Add(0)
+					}
+				}
+			}
+			known = current
+
+			for _, dev := range current {
+				infoSeries.Set(prometheus.Labels{
+					"device": "/dev/" + dev.Device,
+					"vendor": dev.Vendor,
+					"model":  dev.Model,
+					"serial": dev.Serial,
+					"bus":    dev.Bus,
+				}, 1)
+			}
+			infoSeries.Flush()
+
+			<-ticker.C
+		}
+	}()
+}
+
+// discoverRemovableDevices возвращает блочные устройства, помеченные ядром как
+// removable, а также любые накопители на шине USB (внешние USB-диски часто
+// сообщают removable=0).
+func discoverRemovableDevices(root string) map[string]removableDevice {
+	devices := make(map[string]removableDevice)
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return devices
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") || strings.HasPrefix(name, "zram") ||
+			strings.HasPrefix(name, "dm-") || strings.HasPrefix(name, "md") {
+			continue
+		}
+
+		blockDir := filepath.Join(root, name)
+		resolved, err := filepath.EvalSymlinks(filepath.Join(blockDir, "device"))
+		if err != nil {
+			continue
+		}
+
+		bus := removableBusType(resolved)
+		removable := readSysfsValue(filepath.Join(blockDir, "removable")) == "1"
+		if !removable && bus != "usb" {
+			continue
+		}
+
+		// пустой кардридер или привод без носителя
+		if size := readSysfsValue(filepath.Join(blockDir, "size")); size == "" || size == "0" {
+			continue
+		}
+
+		dev := removableDevice{
+			Device: name,
+			Vendor: readSysfsValue(filepath.Join(resolved, "vendor")),
+			Model:  readSysfsValue(filepath.Join(resolved, "model")),
+			Serial: readSysfsValue(filepath.Join(resolved, "serial")),
+			Bus:    bus,
+		}
+
+		if bus == "usb" {
+			if usbDir := findUSBDeviceDir(resolved); usbDir != "" {
+				if dev.Serial == "" {
+					dev.Serial = readSysfsValue(filepath.Join(usbDir, "serial"))
+				}
+				if dev.Vendor == "" {
+					dev.Vendor = readSysfsValue(filepath.Join(usbDir, "manufacturer"))
+				}
+				if dev.Model == "" {
+					dev.Model = readSysfsValue(filepath.Join(usbDir, "product"))
+				}
+			}
+		}
+
+		if dev.Vendor == "" {
+			dev.Vendor = "unknown"
+		}
+		if dev.Model == "" {
+			dev.Model = "unknown"
+		}
+		if dev.Serial == "" {
+			dev.Serial = "unknown"
+		}
+
+		devices[dev.key()] = dev
+	}
+
+	return devices
+}
+
+func removableBusType(devicePath string) string {
+	switch {
+	case strings.Contains(devicePath, "/usb"):
+		return "usb"
+	case strings.Contains(devicePath, "/mmc"):
+		return "mmc"
+	case strings.Contains(devicePath, "/ata"):
+		return "ata"
+	case strings.Contains(devicePath, "/virtio"):
+		return "virtio"
+	default:
+		return "other"
+	}
+}
+
+// findUSBDeviceDir поднимается по дереву sysfs до USB-устройства (каталог с idVendor).
+func findUSBDeviceDir(devicePath string) string {
+	dir := devicePath
+	for dir != "/" && dir != "." && strings.HasPrefix(dir, "/sys/devices") {
+		if _, err := os.Stat(filepath.Join(dir, "idVendor")); err == nil {
+			return dir
+		}
+		dir = filepath.Dir(dir)
+	}
+	return ""
+}
@@ -0,0 +1,318 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const smbiosSysfsRoot = "/sys/firmware/dmi/tables"
+
+const (
+	smbiosTypeBIOS         = 0
+	smbiosTypeSystem       = 1
+	smbiosTypeBaseboard    = 2
+	smbiosTypeProcessor    = 4
+	smbiosTypeMemoryDevice = 17
+	smbiosTypeEndOfTable   = 127
+)
+
+// smbiosStructure — одна структура таблицы SMBIOS: форматированная часть и
+// следующий за ней набор строк, на которые форматированная часть ссылается
+// 1-based индексами.
+type smbiosStructure struct {
+	Type      byte
+	Handle    uint16
+	Formatted []byte
+	Strings   []string
+}
+
+type smbiosTables struct {
+	Major      int
+	Minor      int
+	Structures []smbiosStructure
+}
+
+type smbiosProcessor struct {
+	Socket       string
+	Manufacturer string
+	Version      string
+	MaxSpeedMHz  uint16
+	CoreCount    uint16
+	ThreadCount  uint16
+}
+
+var (
+	smbiosOnce   sync.Once
+	smbiosCached *smbiosTables
+	smbiosErr    error
+)
+
+// loadSMBIOS читает таблицы из sysfs один раз за время работы агента: они не
+// меняются без перезагрузки. Файлы доступны только root.
+func loadSMBIOS() (*smbiosTables, error) {
+	smbiosOnce.Do(func() {
+		smbiosCached, smbiosErr = readSMBIOS(smbiosSysfsRoot)
+	})
+	return smbiosCached, smbiosErr
+}
+
+// readSMBIOS разбирает smbios_entry_point (32-битный "_SM_" или 64-битный
+// "_SM3_") и таблицу DMI из каталога root.
+func readSMBIOS(root string) (*smbiosTables, error) {
+	entry, err := os.ReadFile(filepath.Join(root, "smbios_entry_point"))
+	if err != nil {
+		return nil, err
+	}
+	table, err := os.ReadFile(filepath.Join(root, "DMI"))
+	if err != nil {
+		return nil, err
+	}
+
+	tables := &smbiosTables{}
+	switch {
+	case bytes.HasPrefix(entry, []byte("_SM3_")) && len(entry) >= 0x18:
+		tables.Major, tables.Minor = int(entry[0x07]), int(entry[0x08])
+	case bytes.HasPrefix(entry, []byte("_SM_")) && len(entry) >= 0x1F:
+		tables.Major, tables.Minor = int(entry[0x06]), int(entry[0x07])
+	default:
+		return nil, fmt.Errorf("unknown SMBIOS entry point")
+	}
+
+	tables.Structures, err = parseSMBIOSTable(table)
+	if err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+func parseSMBIOSTable(data []byte) ([]smbiosStructure, error) {
+	var structures []smbiosStructure
+
+	for offset := 0; offset+4 <= len(data); {
+		typ := data[offset]
+		length := int(data[offset+1])
+		if length < 4 || offset+length > len(data) {
+			return structures, fmt.Errorf("malformed SMBIOS structure at offset %d", offset)
+		}
+
+		structure := smbiosStructure{
+			Type:      typ,
+			Handle:    binary.LittleEndian.Uint16(data[offset+2:]),
+			Formatted: data[offset : offset+length],
+		}
+
+		// строковая часть заканчивается двумя нулевыми байтами
+		end := bytes.Index(data[offset+length:], []byte{0, 0})
+		if end < 0 {
+			return structures, fmt.Errorf("unterminated SMBIOS strings at offset %d", offset)
+		}
+		if end > 0 {
+			for _, s := range bytes.Split(data[offset+length:offset+length+end], []byte{0}) {
+				structure.Strings = append(structure.Strings, strings.TrimSpace(string(s)))
+			}
+		}
+
+		structures = append(structures, structure)
+		offset += length + end + 2
+
+		if typ == smbiosTypeEndOfTable {
+			break
+		}
+	}
+
+	return structures, nil
+}
+
+func (s smbiosStructure) byteAt(offset int) (byte, bool) {
+	if offset >= len(s.Formatted) {
+		return 0, false
+	}
+	return s.Formatted[offset], true
+}
+
+func (s smbiosStructure) wordAt(offset int) (uint16, bool) {
+	if offset+2 > len(s.Formatted) {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint16(s.Formatted[offset:]), true
+}
+
+func (s smbiosStructure) dwordAt(offset int) (uint32, bool) {
+	if offset+4 > len(s.Formatted) {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint32(s.Formatted[offset:]), true
+}
+
+// stringAt возвращает строку, на которую ссылается байт по смещению offset.
+func (s smbiosStructure) stringAt(offset int) string {
+	index, ok := s.byteAt(offset)
+	if !ok || index == 0 || int(index) > len(s.Strings) {
+		return ""
+	}
+	return s.Strings[index-1]
+}
+
+func (t *smbiosTables) byType(typ byte) []smbiosStructure {
+	var result []smbiosStructure
+	for _, structure := range t.Structures {
+		if structure.Type == typ {
+			result = append(result, structure)
+		}
+	}
+	return result
+}
+
+// memoryModules декодирует структуры type 17 (Memory Device). Пустые слоты
+// (размер 0) пропускаются.
+func (t *smbiosTables) memoryModules() []memoryModule {
+	var modules []memoryModule
+
+	for _, device := range t.byType(smbiosTypeMemoryDevice) {
+		size, ok := device.wordAt(0x0C)
+		if !ok || size == 0 || size == 0xFFFF {
+			continue
+		}
+
+		var sizeBytes uint64
+		switch {
+		case size == 0x7FFF:
+			// размер >= 32 ГБ хранится в Extended Size (МБ)
+			if extended, ok := device.dwordAt(0x1C); ok {
+				sizeBytes = uint64(extended&0x7FFFFFFF) * 1024 * 1024
+			}
+		case size&0x8000 != 0:
+			sizeBytes = uint64(size&0x7FFF) * 1024
+		default:
+			sizeBytes = uint64(size) * 1024 * 1024
+		}
+
+		module := memoryModule{
+			SizeBytes:    sizeBytes,
+			Locator:      device.stringAt(0x10),
+			Manufacturer: sanitizeMemoryField(device.stringAt(0x17)),
+			SerialNumber: sanitizeMemoryField(device.stringAt(0x18)),
+			PartNumber:   sanitizeMemoryField(device.stringAt(0x1A)),
+			Speed:        smbiosMemorySpeed(device),
+		}
+
+		if shouldSkipModule(module) {
+			continue
+		}
+		modules = append(modules, module)
+	}
+
+	return modules
+}
+
+// smbiosMemorySpeed возвращает настроенную скорость модуля (Configured Memory
+// Speed, SMBIOS 2.7+), а при её отсутствии — максимальную (Speed). Значение
+// 0xFFFF означает, что скорость указана в расширенном поле (SMBIOS 3.3+).
+func smbiosMemorySpeed(device smbiosStructure) string {
+	for _, field := range []struct{ offset, extended int }{{0x20, 0x58}, {0x15, 0x54}} {
+		speed, ok := device.wordAt(field.offset)
+		if !ok || speed == 0 {
+			continue
+		}
+		if speed == 0xFFFF {
+			extended, ok := device.dwordAt(field.extended)
+			if !ok || extended == 0 {
+				continue
+			}
+			return fmt.Sprintf("%dMT/s", extended)
+		}
+		return fmt.Sprintf("%dMT/s", speed)
+	}
+	return "unknown"
+}
+
+func (t *smbiosTables) processors() []smbiosProcessor {
+	var result []smbiosProcessor
+
+	for _, proc := range t.byType(smbiosTypeProcessor) {
+		// Status (0x18): бит 6 — сокет заполнен
+		if status, ok := proc.byteAt(0x18); ok && status&0x40 == 0 {
+			continue
+		}
+
+		processor := smbiosProcessor{
+			Socket:       proc.stringAt(0x04),
+			Manufacturer: proc.stringAt(0x07),
+			Version:      proc.stringAt(0x10),
+		}
+		processor.MaxSpeedMHz, _ = proc.wordAt(0x14)
+
+		if count, ok := proc.byteAt(0x23); ok {
+			processor.CoreCount = uint16(count)
+		}
+		if count, ok := proc.byteAt(0x25); ok {
+			processor.ThreadCount = uint16(count)
+		}
+		// значение 0xFF означает, что количество указано в полях Core/Thread Count 2
+		if processor.CoreCount == 0xFF {
+			processor.CoreCount, _ = proc.wordAt(0x2A)
+		}
+		if processor.ThreadCount == 0xFF {
+			processor.ThreadCount, _ = proc.wordAt(0x2E)
+		}
+
+		result = append(result, processor)
+	}
+
+	return result
+}
+
+// smbiosDMIFields сопоставляет имена файлов /sys/class/dmi/id с полями структур
+// SMBIOS type 0/1/2.
+var smbiosDMIFields = map[string]struct {
+	typ    byte
+	offset int
+}{
+	"bios_vendor":     {smbiosTypeBIOS, 0x04},
+	"bios_version":    {smbiosTypeBIOS, 0x05},
+	"bios_date":       {smbiosTypeBIOS, 0x08},
+	"sys_vendor":      {smbiosTypeSystem, 0x04},
+	"product_name":    {smbiosTypeSystem, 0x05},
+	"product_version": {smbiosTypeSystem, 0x06},
+	"product_serial":  {smbiosTypeSystem, 0x07},
+	"board_vendor":    {smbiosTypeBaseboard, 0x04},
+	"board_name":      {smbiosTypeBaseboard, 0x05},
+	"board_version":   {smbiosTypeBaseboard, 0x06},
+	"board_serial":    {smbiosTypeBaseboard, 0x07},
+}
+
+// dmiField возвращает поле из /sys/class/dmi/id, а если оно пустое или
+// недоступно — из таблиц SMBIOS.
+func dmiField(name string) string {
+	if value := readDMIField(name); value != "" {
+		return value
+	}
+
+	tables, err := loadSMBIOS()
+	if err != nil {
+		return ""
+	}
+	return tables.dmiField(name)
+}
+
+// dmiField возвращает поле name (в терминах /sys/class/dmi/id) из первой
+// структуры SMBIOS, где оно заполнено.
+func (t *smbiosTables) dmiField(name string) string {
+	field, ok := smbiosDMIFields[name]
+	if !ok {
+		return ""
+	}
+	for _, structure := range t.byType(field.typ) {
+		if value := structure.stringAt(field.offset); value != "" {
+			return value
+		}
+	}
+	return ""
+}
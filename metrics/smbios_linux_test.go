@@ -0,0 +1,243 @@
+//go:build linux
+
+package metrics
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Каталоги testdata/smbios/* повторяют /sys/firmware/dmi/tables:
+// smbios_entry_point и DMI. qemu_2.8 — таблицы QEMU/SeaBIOS с 32-битной точкой
+// входа "_SM_". synthetic_3.3 собран вручную и не снят с реального сервера:
+// двухсокетная плата с 64-битной точкой входа "_SM3_", пустым вторым сокетом,
+// модулями >= 32 ГБ и пустым слотом памяти; вендор и серийные номера вымышлены.
+
+func TestReadSMBIOS(t *testing.T) {
+	tests := []struct {
+		dir        string
+		major      int
+		minor      int
+		structures int
+		dmi        map[string]string
+		processors []smbiosProcessor
+		modules    []memoryModule
+	}{
+		{
+			dir:        "qemu_2.8",
+			major:      2,
+			minor:      8,
+			structures: 10,
+			dmi: map[string]string{
+				"bios_vendor":     "SeaBIOS",
+				"bios_version":    "1.16.3-debian-1.16.3-2",
+				"bios_date":       "04/01/2014",
+				"sys_vendor":      "QEMU",
+				"product_name":    "Standard PC (i440FX + PIIX, 1996)",
+				"product_version": "pc-i440fx-8.2",
+				"product_serial":  "",
+				// QEMU не создаёт type 2
+				"board_vendor": "",
+				"board_name":   "",
+			},
+			processors: []smbiosProcessor{
+				{Socket: "CPU 0", Manufacturer: "QEMU", Version: "pc-i440fx-8.2", MaxSpeedMHz: 2000, CoreCount: 2, ThreadCount: 2},
+				{Socket: "CPU 1", Manufacturer: "QEMU", Version: "pc-i440fx-8.2", MaxSpeedMHz: 2000, CoreCount: 2, ThreadCount: 2},
+			},
+			modules: []memoryModule{
+				{SizeBytes: 8 << 30, Locator: "DIMM 0", Manufacturer: "QEMU", PartNumber: "unknown", SerialNumber: "unknown", Speed: "unknown"},
+			},
+		},
+		{
+			dir:        "synthetic_3.3",
+			major:      3,
+			minor:      3,
+			structures: 12,
+			dmi: map[string]string{
+				"bios_vendor":     "Example Corp.",
+				"bios_version":    "1.10.2",
+				"bios_date":       "06/05/2023",
+				"sys_vendor":      "Example Corp.",
+				"product_name":    "Synthetic Server S1",
+				"product_version": "Not Specified",
+				"product_serial":  "SN00001",
+				"board_vendor":    "Example Corp.",
+				"board_name":      "BRD0001",
+				"board_version":   "A03",
+				"board_serial":    ".SN00001.BRDSN0000001.",
+			},
+			// CPU2 — пустой сокет
+			processors: []smbiosProcessor{
+				{Socket: "CPU1", Manufacturer: "Intel", Version: "Intel(R) Xeon(R) Gold 6338 CPU @ 2.00GHz", MaxSpeedMHz: 4000, CoreCount: 32, ThreadCount: 64},
+			},
+			// слот A4 пуст
+			modules: []memoryModule{
+				{SizeBytes: 64 << 30, Locator: "A1", Manufacturer: "00AD063200AD", PartNumber: "HMAA8GR7CJR4N-XN", SerialNumber: "8A2F1C3D", Speed: "2933MT/s"},
+				{SizeBytes: 32 << 30, Locator: "A2", Manufacturer: "Samsung", PartNumber: "M393A4K40DB3-CWE", SerialNumber: "03A1B2C4", Speed: "2933MT/s"},
+				{SizeBytes: 16 << 30, Locator: "A3", Manufacturer: "Micron Technology", PartNumber: "18ASF2G72PDZ-3G2E1", SerialNumber: "2F4C6E81", Speed: "2933MT/s"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tables, err := readSMBIOS(filepath.Join("testdata", "smbios", tt.dir))
+		if err != nil {
+			t.Errorf("%s: readSMBIOS() error: %v", tt.dir, err)
+			continue
+		}
+
+		if tables.Major != tt.major || tables.Minor != tt.minor {
+			t.Errorf("%s: version = %d.%d, want %d.%d", tt.dir, tables.Major, tables.Minor, tt.major, tt.minor)
+		}
+		if len(tables.Structures) != tt.structures {
+			t.Errorf("%s: %d structures, want %d", tt.dir, len(tables.Structures), tt.structures)
+		}
+		if last := tables.Structures[len(tables.Structures)-1]; last.Type != smbiosTypeEndOfTable {
+			t.Errorf("%s: last structure type = %d, want end of table", tt.dir, last.Type)
+		}
+
+		for name, want := range tt.dmi {
+			if got := tables.dmiField(name); got != want {
+				t.Errorf("%s: dmiField(%q) = %q, want %q", tt.dir, name, got, want)
+			}
+		}
+		if got := tables.processors(); !reflect.DeepEqual(got, tt.processors) {
+			t.Errorf("%s: processors() = %+v, want %+v", tt.dir, got, tt.processors)
+		}
+		if got := tables.memoryModules(); !reflect.DeepEqual(got, tt.modules) {
+			t.Errorf("%s: memoryModules() = %+v, want %+v", tt.dir, got, tt.modules)
+		}
+	}
+}
+
+func TestReadSMBIOSErrors(t *testing.T) {
+	if _, err := readSMBIOS(filepath.Join("testdata", "smbios", "missing")); err == nil {
+		t.Error("readSMBIOS(missing) succeeded, want error")
+	}
+}
+
+func TestParseSMBIOSTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []smbiosStructure
+		wantErr bool
+	}{
+		{
+			name: "strings and end of table",
+			data: []byte{
+				1, 8, 0x00, 0x01, 1, 2, 0, 0, 'Q', 'E', 'M', 'U', 0, ' ', 'p', 'c', ' ', 0, 0,
+				127, 4, 0x00, 0x7F, 0, 0,
+				// данные после type 127 не разбираются
+				0xFF, 0xFF,
+			},
+			want: []smbiosStructure{
+				{Type: 1, Handle: 0x0100, Formatted: []byte{1, 8, 0x00, 0x01, 1, 2, 0, 0}, Strings: []string{"QEMU", "pc"}},
+				{Type: 127, Handle: 0x7F00, Formatted: []byte{127, 4, 0x00, 0x7F}},
+			},
+		},
+		{
+			name:    "length below header",
+			data:    []byte{1, 2, 0, 0, 0, 0},
+			wantErr: true,
+		},
+		{
+			name:    "formatted area past end",
+			data:    []byte{17, 0x28, 0, 0x11, 0, 0},
+			wantErr: true,
+		},
+		{
+			name:    "unterminated strings",
+			data:    []byte{0, 4, 0, 0, 'S', 'e', 'a', 0},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSMBIOSTable(tt.data)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseSMBIOSTable() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// smbiosMemoryDevice собирает структуру type 17 SMBIOS 3.3 с заданными полями.
+func smbiosMemoryDevice(size uint16, extended uint32, speed, configured uint16, extSpeed, extConfigured uint32) smbiosStructure {
+	formatted := make([]byte, 0x5C)
+	formatted[0], formatted[1] = smbiosTypeMemoryDevice, 0x5C
+	binary.LittleEndian.PutUint16(formatted[0x0C:], size)
+	formatted[0x10] = 1
+	binary.LittleEndian.PutUint16(formatted[0x15:], speed)
+	formatted[0x17] = 2
+	binary.LittleEndian.PutUint32(formatted[0x1C:], extended)
+	binary.LittleEndian.PutUint16(formatted[0x20:], configured)
+	binary.LittleEndian.PutUint32(formatted[0x54:], extSpeed)
+	binary.LittleEndian.PutUint32(formatted[0x58:], extConfigured)
+	return smbiosStructure{Type: smbiosTypeMemoryDevice, Formatted: formatted, Strings: []string{"DIMM_A1", "Kingston"}}
+}
+
+func TestSMBIOSMemoryModules(t *testing.T) {
+	tests := []struct {
+		name        string
+		device      smbiosStructure
+		wantSize    uint64
+		wantSpeed   string
+		wantSkipped bool
+	}{
+		{"size in MB", smbiosMemoryDevice(8192, 0, 3200, 3200, 0, 0), 8 << 30, "3200MT/s", false},
+		{"extended size", smbiosMemoryDevice(0x7FFF, 131072, 4800, 4400, 0, 0), 128 << 30, "4400MT/s", false},
+		{"size in KB below threshold", smbiosMemoryDevice(0x8000|0x4000, 0, 0, 0, 0, 0), 0, "", true},
+		{"empty slot", smbiosMemoryDevice(0, 0, 0, 0, 0, 0), 0, "", true},
+		{"unknown size", smbiosMemoryDevice(0xFFFF, 0, 0, 0, 0, 0), 0, "", true},
+		{"configured speed missing", smbiosMemoryDevice(16384, 0, 2666, 0, 0, 0), 16 << 30, "2666MT/s", false},
+		{"extended configured speed", smbiosMemoryDevice(16384, 0, 0xFFFF, 0xFFFF, 70000, 68000), 16 << 30, "68000MT/s", false},
+		{"extended speed only", smbiosMemoryDevice(16384, 0, 0xFFFF, 0, 70000, 0), 16 << 30, "70000MT/s", false},
+	}
+
+	for _, tt := range tests {
+		tables := &smbiosTables{Structures: []smbiosStructure{tt.device}}
+		modules := tables.memoryModules()
+
+		if tt.wantSkipped {
+			if len(modules) != 0 {
+				t.Errorf("%s: memoryModules() = %+v, want none", tt.name, modules)
+			}
+			continue
+		}
+		if len(modules) != 1 {
+			t.Errorf("%s: memoryModules() returned %d modules, want 1", tt.name, len(modules))
+			continue
+		}
+		if modules[0].SizeBytes != tt.wantSize || modules[0].Speed != tt.wantSpeed {
+			t.Errorf("%s: size %d speed %q, want %d %q", tt.name, modules[0].SizeBytes, modules[0].Speed, tt.wantSize, tt.wantSpeed)
+		}
+		if modules[0].Locator != "DIMM_A1" || modules[0].Manufacturer != "Kingston" {
+			t.Errorf("%s: locator %q manufacturer %q", tt.name, modules[0].Locator, modules[0].Manufacturer)
+		}
+	}
+}
+
+func TestSMBIOSProcessorCoreCount2(t *testing.T) {
+	formatted := make([]byte, 0x30)
+	formatted[0], formatted[1] = smbiosTypeProcessor, 0x30
+	formatted[0x04] = 1
+	formatted[0x18] = 0x41
+	formatted[0x23], formatted[0x25] = 0xFF, 0xFF
+	binary.LittleEndian.PutUint16(formatted[0x2A:], 288)
+	binary.LittleEndian.PutUint16(formatted[0x2E:], 576)
+
+	tables := &smbiosTables{Structures: []smbiosStructure{
+		{Type: smbiosTypeProcessor, Formatted: formatted, Strings: []string{"P0"}},
+	}}
+
+	want := []smbiosProcessor{{Socket: "P0", CoreCount: 288, ThreadCount: 576}}
+	if got := tables.processors(); !reflect.DeepEqual(got, want) {
+		t.Errorf("processors() = %+v, want %+v", got, want)
+	}
+}
@@ -19,12 +19,12 @@ func RecordSystemMetrics() {
 			return
 		}
 
-		manufacturer := readSysfsValue("/sys/class/dmi/id/sys_vendor")
+		manufacturer := dmiField("sys_vendor")
 		if manufacturer == "" {
 			manufacturer = info.Platform
 		}
 
-		model := readSysfsValue("/sys/class/dmi/id/product_name")
+		model := dmiField("product_name")
 		if model == "" {
 			model = info.KernelVersion
 		}
@@ -0,0 +1,133 @@
+//go:build linux
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	systemdBusName       = "org.freedesktop.systemd1"
+	systemdObjectPath    = "/org/freedesktop/systemd1"
+	systemdPrivateSocket = "unix:path=/run/systemd/private"
+
+	// systemdCallTimeout ограничивает вызов D-Bus: зависший systemd не должен
+	// останавливать цикл опроса
+	systemdCallTimeout = 10 * time.Second
+)
+
+// systemdUnit — юнит из ListUnits менеджера systemd.
+type systemdUnit struct {
+	Name        string
+	LoadState   string
+	ActiveState string
+	SubState    string
+	Path        string
+}
+
+// systemdClient — операции с менеджером systemd, нужные сборщику. Реализация
+// поверх D-Bus подменяется заглушкой, чтобы проверять сборщик без systemd.
+type systemdClient interface {
+	ListUnits() ([]systemdUnit, error)
+	ServiceRestarts(path string) (uint32, error)
+	Close() error
+}
+
+type dbusSystemdClient struct {
+	conn *dbus.Conn
+}
+
+// newSystemdClient подключается к приватному сокету systemd (доступен root и
+// не зависит от dbus-daemon), а при неудаче — к системной шине.
+func newSystemdClient() (systemdClient, error) {
+	conn, privateErr := dialSystemdPrivate()
+	if privateErr == nil {
+		return &dbusSystemdClient{conn: conn}, nil
+	}
+
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("private socket: %v; system bus: %v", privateErr, err)
+	}
+	return &dbusSystemdClient{conn: conn}, nil
+}
+
+// dialSystemdPrivate открывает соединение точка-точка с systemd. Hello на
+// приватном сокете не вызывается: шины там нет.
+func dialSystemdPrivate() (*dbus.Conn, error) {
+	conn, err := dbus.Dial(systemdPrivateSocket)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+	if err := conn.Auth(methods); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c *dbusSystemdClient) ListUnits() ([]systemdUnit, error) {
+	// сигнатура ответа ListUnits: a(ssssssouso)
+	var raw []struct {
+		Name        string
+		Description string
+		LoadState   string
+		ActiveState string
+		SubState    string
+		Following   string
+		Path        dbus.ObjectPath
+		JobID       uint32
+		JobType     string
+		JobPath     dbus.ObjectPath
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), systemdCallTimeout)
+	defer cancel()
+
+	obj := c.conn.Object(systemdBusName, systemdObjectPath)
+	if err := obj.CallWithContext(ctx, systemdBusName+".Manager.ListUnits", 0).Store(&raw); err != nil {
+		return nil, err
+	}
+
+	units := make([]systemdUnit, 0, len(raw))
+	for _, unit := range raw {
+		units = append(units, systemdUnit{
+			Name:        unit.Name,
+			LoadState:   unit.LoadState,
+			ActiveState: unit.ActiveState,
+			SubState:    unit.SubState,
+			Path:        string(unit.Path),
+		})
+	}
+	return units, nil
+}
+
+func (c *dbusSystemdClient) ServiceRestarts(path string) (uint32, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), systemdCallTimeout)
+	defer cancel()
+
+	var variant dbus.Variant
+	obj := c.conn.Object(systemdBusName, dbus.ObjectPath(path))
+	call := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, systemdBusName+".Service", "NRestarts")
+	if err := call.Store(&variant); err != nil {
+		return 0, err
+	}
+
+	restarts, ok := variant.Value().(uint32)
+	if !ok {
+		return 0, fmt.Errorf("unexpected NRestarts type %s", variant.Signature())
+	}
+	return restarts, nil
+}
+
+func (c *dbusSystemdClient) Close() error {
+	return c.conn.Close()
+}
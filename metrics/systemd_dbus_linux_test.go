@@ -0,0 +1,175 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// peerAuthConn подменяет обмен SASL на соединении точка-точка: godbus умеет
+// аутентифицироваться только как клиент, поэтому обе стороны получают
+// заготовленные ответы, а после BEGIN работают с каналом напрямую.
+type peerAuthConn struct {
+	net.Conn
+
+	mu      sync.Mutex
+	script  []byte
+	written []byte
+	began   bool
+}
+
+func (c *peerAuthConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	if len(c.script) > 0 {
+		n := copy(p, c.script)
+		c.script = c.script[n:]
+		c.mu.Unlock()
+		return n, nil
+	}
+	c.mu.Unlock()
+	return c.Conn.Read(p)
+}
+
+func (c *peerAuthConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	if !c.began {
+		c.written = append(c.written, p...)
+		c.began = bytes.Contains(c.written, []byte("BEGIN\r\n"))
+		c.mu.Unlock()
+		return len(p), nil
+	}
+	c.mu.Unlock()
+	return c.Conn.Write(p)
+}
+
+// peerAuth — механизм, который заготовленный ответ сразу принимает.
+type peerAuth struct{}
+
+func (peerAuth) FirstData() ([]byte, []byte, dbus.AuthStatus) {
+	return []byte("PEER"), nil, dbus.AuthOk
+}
+
+func (peerAuth) HandleData([]byte) ([]byte, dbus.AuthStatus) {
+	return nil, dbus.AuthOk
+}
+
+func newPeerConn(t *testing.T, raw net.Conn) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.NewConn(&peerAuthConn{Conn: raw, script: []byte("REJECTED PEER\r\nOK 0123456789abcdef\r\n")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Auth([]dbus.Auth{peerAuth{}}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// fakeSystemdListedUnit повторяет структуру ответа ListUnits: a(ssssssouso).
+type fakeSystemdListedUnit struct {
+	Name        string
+	Description string
+	LoadState   string
+	ActiveState string
+	SubState    string
+	Following   string
+	Path        dbus.ObjectPath
+	JobID       uint32
+	JobType     string
+	JobPath     dbus.ObjectPath
+}
+
+type fakeSystemdManager struct {
+	units []fakeSystemdListedUnit
+}
+
+func (m *fakeSystemdManager) ListUnits() ([]fakeSystemdListedUnit, *dbus.Error) {
+	return m.units, nil
+}
+
+// fakeSystemdUnitProperties отдаёт NRestarts юнита через
+// org.freedesktop.DBus.Properties.
+type fakeSystemdUnitProperties struct {
+	restarts dbus.Variant
+}
+
+func (p *fakeSystemdUnitProperties) Get(iface, property string) (dbus.Variant, *dbus.Error) {
+	if iface != systemdBusName+".Service" || property != "NRestarts" {
+		return dbus.Variant{}, dbus.NewError("org.freedesktop.DBus.Error.UnknownProperty", []interface{}{property})
+	}
+	return p.restarts, nil
+}
+
+func TestDbusSystemdClient(t *testing.T) {
+	serverRaw, clientRaw := net.Pipe()
+	server := newPeerConn(t, serverRaw)
+
+	manager := &fakeSystemdManager{units: []fakeSystemdListedUnit{
+		{
+			Name:        "nginx.service",
+			Description: "A high performance web server",
+			LoadState:   "loaded",
+			ActiveState: "active",
+			SubState:    "running",
+			Path:        "/org/freedesktop/systemd1/unit/nginx_2eservice",
+			JobPath:     "/",
+		},
+		{
+			Name:        "backup.service",
+			Description: "Nightly backup",
+			LoadState:   "loaded",
+			ActiveState: "activating",
+			SubState:    "start",
+			Path:        "/org/freedesktop/systemd1/unit/backup_2eservice",
+			JobID:       42,
+			JobType:     "start",
+			JobPath:     "/org/freedesktop/systemd1/job/42",
+		},
+	}}
+	if err := server.Export(manager, systemdObjectPath, systemdBusName+".Manager"); err != nil {
+		t.Fatal(err)
+	}
+	properties := map[dbus.ObjectPath]dbus.Variant{
+		"/org/freedesktop/systemd1/unit/nginx_2eservice": dbus.MakeVariant(uint32(3)),
+		// тип, отличный от u, не принимается за число перезапусков
+		"/org/freedesktop/systemd1/unit/backup_2eservice": dbus.MakeVariant("3"),
+	}
+	for path, restarts := range properties {
+		if err := server.Export(&fakeSystemdUnitProperties{restarts: restarts}, path, "org.freedesktop.DBus.Properties"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := &dbusSystemdClient{conn: newPeerConn(t, clientRaw)}
+
+	units, err := client.ListUnits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []systemdUnit{
+		{Name: "nginx.service", LoadState: "loaded", ActiveState: "active", SubState: "running", Path: "/org/freedesktop/systemd1/unit/nginx_2eservice"},
+		{Name: "backup.service", LoadState: "loaded", ActiveState: "activating", SubState: "start", Path: "/org/freedesktop/systemd1/unit/backup_2eservice"},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("ListUnits() = %+v, want %+v", units, want)
+	}
+
+	if restarts, err := client.ServiceRestarts("/org/freedesktop/systemd1/unit/nginx_2eservice"); err != nil || restarts != 3 {
+		t.Errorf("ServiceRestarts(nginx) = %d, %v, want 3", restarts, err)
+	}
+	if _, err := client.ServiceRestarts("/org/freedesktop/systemd1/unit/backup_2eservice"); err == nil || !strings.Contains(err.Error(), "unexpected NRestarts type s") {
+		t.Errorf("ServiceRestarts(backup) error = %v, want unexpected type", err)
+	}
+	// юнит без объекта: systemd старше 235 или юнит уже выгружен
+	if _, err := client.ServiceRestarts("/org/freedesktop/systemd1/unit/gone_2eservice"); err == nil {
+		t.Error("ServiceRestarts(gone) succeeded for unknown object")
+	}
+}
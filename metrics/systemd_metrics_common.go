@@ -0,0 +1,42 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	SystemdUp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "systemd_up",
+			Help: "Whether systemd was reachable over D-Bus in the last cycle (1 = yes, 0 = no)",
+		},
+	)
+
+	SystemdUnitState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "systemd_unit_state",
+			Help: "State of systemd unit (active_state: active, inactive, failed...; sub_state: running, exited, dead...), 1 for current state",
+		},
+		[]string{"unit", "type", "active_state", "sub_state"},
+	)
+
+	SystemdUnitActive = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "systemd_unit_active",
+			Help: "Whether systemd unit is active (1 = active, 0 = otherwise)",
+		},
+		[]string{"unit", "type"},
+	)
+
+	SystemdFailedUnits = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "systemd_failed_units",
+			Help: "Number of loaded systemd units in failed state, regardless of include/exclude filters",
+		},
+	)
+)
+
+// NRestarts ведёт systemd, агент публикует его значение.
+var SystemdServiceRestarts = newConstCounterVec(
+	"systemd_service_restarts_total",
+	"Number of automatic restarts of systemd service (NRestarts); reset by systemd on manual restart",
+	[]string{"unit"},
+)
@@ -0,0 +1,209 @@
+//go:build linux
+
+package metrics
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"node_exporter_custom/internal/deviceconfig"
+)
+
+// systemdDefaultInclude — юниты, публикуемые без явного списка include.
+var systemdDefaultInclude = regexp.MustCompile(`\.service$`)
+
+type systemdUnitFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func newSystemdUnitFilter(cfg deviceconfig.SystemdConfig) systemdUnitFilter {
+	filter := systemdUnitFilter{
+		include: compileUnitPatterns(cfg.Include),
+		exclude: compileUnitPatterns(cfg.Exclude),
+	}
+	if len(filter.include) == 0 {
+		filter.include = []*regexp.Regexp{systemdDefaultInclude}
+	}
+	return filter
+}
+
+// compileUnitPatterns компилирует выражения; некорректное выражение
+// пропускается с записью в лог.
+func compileUnitPatterns(patterns []string) []*regexp.Regexp {
+	var result []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Printf("invalid systemd unit pattern %q: %v", pattern, err)
+			continue
+		}
+		result = append(result, re)
+	}
+	return result
+}
+
+func (f systemdUnitFilter) allowed(unit string) bool {
+	included := false
+	for _, re := range f.include {
+		if re.MatchString(unit) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+
+	for _, re := range f.exclude {
+		if re.MatchString(unit) {
+			return false
+		}
+	}
+	return true
+}
+
+func RecordSystemdMetrics(cfg deviceconfig.SystemdConfig) {
+	filter := newSystemdUnitFilter(cfg)
+
+	go func() {
+		ticker := time.NewTicker(cfg.EffectiveInterval())
+		defer ticker.Stop()
+
+		collector := newSystemdCollector(filter)
+		var client systemdClient
+		reachable := true
+
+		for {
+			if client == nil {
+				var err error
+				client, err = newSystemdClient()
+				if err != nil {
+					// на хостах без systemd ошибка повторяется каждый цикл
+					if reachable {
+						log.Printf("failed to connect to systemd: %v", err)
+					}
+					reachable = false
+					collector.unavailable()
+					<-ticker.C
+					continue
+				}
+			}
+
+			if err := collector.collect(client); err != nil {
+				log.Printf("failed to read systemd units: %v", err)
+				client.Close()
+				client = nil
+				collector.unavailable()
+			} else {
+				reachable = true
+			}
+
+			<-ticker.C
+		}
+	}()
+}
+
+// systemdCollector публикует состояние юнитов, полученное через systemdClient.
+// Все вызовы D-Bus выполняются до публикации, затем серии обновляются на месте:
+// scrape во время опроса не видит пустых векторов, а серии исчезнувших юнитов
+// и прежних состояний удаляются.
+type systemdCollector struct {
+	filter systemdUnitFilter
+	state  *gaugeSeries
+	active *gaugeSeries
+}
+
+// systemdUnitStatus — отфильтрованный юнит с числом перезапусков службы.
+type systemdUnitStatus struct {
+	systemdUnit
+	Type        string
+	Restarts    uint32
+	HasRestarts bool
+}
+
+func newSystemdCollector(filter systemdUnitFilter) *systemdCollector {
+	return &systemdCollector{
+		filter: filter,
+		state:  newGaugeSeries(SystemdUnitState),
+		active: newGaugeSeries(SystemdUnitActive),
+	}
+}
+
+func (c *systemdCollector) collect(client systemdClient) error {
+	units, err := client.ListUnits()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	var statuses []systemdUnitStatus
+	for _, unit := range units {
+		if unit.LoadState == "loaded" && unit.ActiveState == "failed" {
+			failed++
+		}
+		// not-found — юниты, на которые есть ссылки, но нет файла юнита
+		if unit.LoadState == "not-found" || !c.filter.allowed(unit.Name) {
+			continue
+		}
+
+		status := systemdUnitStatus{systemdUnit: unit, Type: systemdUnitType(unit.Name)}
+		if status.Type == "service" {
+			// NRestarts нет в systemd старше 235
+			if restarts, err := client.ServiceRestarts(unit.Path); err == nil {
+				status.Restarts, status.HasRestarts = restarts, true
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	restarts := SystemdServiceRestarts.Batch()
+	for _, status := range statuses {
+		c.state.Set(prometheus.Labels{
+			"unit":         status.Name,
+			"type":         status.Type,
+			"active_state": status.ActiveState,
+			"sub_state":    status.SubState,
+		}, 1)
+
+		active := 0.0
+		if status.ActiveState == "active" {
+			active = 1
+		}
+		c.active.Set(prometheus.Labels{"unit": status.Name, "type": status.Type}, active)
+
+		// NRestarts публикуется как есть: systemd обнуляет его при ручном
+		// перезапуске, и Prometheus видит это как сброс счётчика
+		if status.HasRestarts {
+			restarts.Set(prometheus.Labels{"unit": status.Name}, float64(status.Restarts))
+		}
+	}
+
+	c.state.Flush()
+	c.active.Flush()
+	restarts.Commit()
+	SystemdFailedUnits.Set(float64(failed))
+	SystemdUp.Set(1)
+	return nil
+}
+
+// unavailable убирает состояние юнитов, пока systemd недоступен, чтобы не
+// публиковать устаревшие значения.
+func (c *systemdCollector) unavailable() {
+	SystemdUp.Set(0)
+	c.state.Flush()
+	c.active.Flush()
+	SystemdServiceRestarts.Batch().Commit()
+	SystemdFailedUnits.Set(0)
+}
+
+func systemdUnitType(unit string) string {
+	if idx := strings.LastIndex(unit, "."); idx >= 0 {
+		return unit[idx+1:]
+	}
+	return "unknown"
+}
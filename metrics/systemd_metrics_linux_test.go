@@ -0,0 +1,228 @@
+//go:build linux
+
+package metrics
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"node_exporter_custom/internal/deviceconfig"
+)
+
+// fakeSystemdClient отдаёт заранее заданные юниты вместо D-Bus.
+type fakeSystemdClient struct {
+	units    []systemdUnit
+	restarts map[string]uint32
+	err      error
+}
+
+func (c *fakeSystemdClient) ListUnits() ([]systemdUnit, error) {
+	return c.units, c.err
+}
+
+func (c *fakeSystemdClient) ServiceRestarts(path string) (uint32, error) {
+	restarts, ok := c.restarts[path]
+	if !ok {
+		return 0, errors.New("unknown property NRestarts")
+	}
+	return restarts, nil
+}
+
+func (c *fakeSystemdClient) Close() error {
+	return nil
+}
+
+func fakeUnit(name, load, active, sub string) systemdUnit {
+	return systemdUnit{
+		Name:        name,
+		LoadState:   load,
+		ActiveState: active,
+		SubState:    sub,
+		Path:        "/org/freedesktop/systemd1/unit/" + name,
+	}
+}
+
+func gatherSystemd(t *testing.T) []string {
+	t.Helper()
+	return gatherSeries(t, SystemdUp, SystemdUnitState, SystemdUnitActive, SystemdServiceRestarts, SystemdFailedUnits)
+}
+
+func resetSystemdMetrics() {
+	SystemdUp.Set(0)
+	SystemdUnitState.Reset()
+	SystemdUnitActive.Reset()
+	SystemdServiceRestarts.Reset()
+	SystemdFailedUnits.Set(0)
+}
+
+func TestSystemdCollectorFiltering(t *testing.T) {
+	resetSystemdMetrics()
+
+	client := &fakeSystemdClient{
+		units: []systemdUnit{
+			fakeUnit("nginx.service", "loaded", "active", "running"),
+			fakeUnit("backup.service", "loaded", "failed", "failed"),
+			fakeUnit("debug-shell.service", "loaded", "inactive", "dead"),
+			fakeUnit("ssh.socket", "loaded", "active", "listening"),
+			fakeUnit("cron.timer", "loaded", "active", "waiting"),
+			// не проходит фильтр, но учитывается в systemd_failed_units
+			fakeUnit("mnt-backup.mount", "loaded", "failed", "failed"),
+			// ссылка на удалённый юнит: не публикуется и не считается
+			fakeUnit("legacy.service", "not-found", "failed", "failed"),
+		},
+		restarts: map[string]uint32{
+			"/org/freedesktop/systemd1/unit/nginx.service":  2,
+			"/org/freedesktop/systemd1/unit/backup.service": 0,
+		},
+	}
+
+	collector := newSystemdCollector(newSystemdUnitFilter(deviceconfig.SystemdConfig{
+		Include: []string{`\.service$`, `^ssh\.socket$`},
+		Exclude: []string{`^debug-`},
+	}))
+	if err := collector.collect(client); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"systemd_failed_units{} 2",
+		"systemd_service_restarts_total{unit=backup.service} 0",
+		"systemd_service_restarts_total{unit=nginx.service} 2",
+		"systemd_unit_active{type=service,unit=backup.service} 0",
+		"systemd_unit_active{type=service,unit=nginx.service} 1",
+		"systemd_unit_active{type=socket,unit=ssh.socket} 1",
+		"systemd_unit_state{active_state=active,sub_state=listening,type=socket,unit=ssh.socket} 1",
+		"systemd_unit_state{active_state=active,sub_state=running,type=service,unit=nginx.service} 1",
+		"systemd_unit_state{active_state=failed,sub_state=failed,type=service,unit=backup.service} 1",
+		"systemd_up{} 1",
+	}
+	if got := gatherSystemd(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("series:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSystemdCollectorStateChangesAndRestarts(t *testing.T) {
+	resetSystemdMetrics()
+
+	collector := newSystemdCollector(newSystemdUnitFilter(deviceconfig.SystemdConfig{}))
+	client := &fakeSystemdClient{
+		units: []systemdUnit{
+			fakeUnit("app.service", "loaded", "activating", "auto-restart"),
+			fakeUnit("worker.service", "loaded", "active", "running"),
+		},
+		restarts: map[string]uint32{
+			"/org/freedesktop/systemd1/unit/app.service":    5,
+			"/org/freedesktop/systemd1/unit/worker.service": 1,
+		},
+	}
+	if err := collector.collect(client); err != nil {
+		t.Fatal(err)
+	}
+
+	// перезапуски, накопленные до запуска агента, видны сразу
+	want := []string{
+		"systemd_failed_units{} 0",
+		"systemd_service_restarts_total{unit=app.service} 5",
+		"systemd_service_restarts_total{unit=worker.service} 1",
+		"systemd_unit_active{type=service,unit=app.service} 0",
+		"systemd_unit_active{type=service,unit=worker.service} 1",
+		"systemd_unit_state{active_state=activating,sub_state=auto-restart,type=service,unit=app.service} 1",
+		"systemd_unit_state{active_state=active,sub_state=running,type=service,unit=worker.service} 1",
+		"systemd_up{} 1",
+	}
+	if got := gatherSystemd(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("first cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// app перезапущен вручную (NRestarts обнулён), worker удалён
+	client.units = []systemdUnit{fakeUnit("app.service", "loaded", "active", "running")}
+	client.restarts = map[string]uint32{"/org/freedesktop/systemd1/unit/app.service": 0}
+	if err := collector.collect(client); err != nil {
+		t.Fatal(err)
+	}
+
+	want = []string{
+		"systemd_failed_units{} 0",
+		"systemd_service_restarts_total{unit=app.service} 0",
+		"systemd_unit_active{type=service,unit=app.service} 1",
+		"systemd_unit_state{active_state=active,sub_state=running,type=service,unit=app.service} 1",
+		"systemd_up{} 1",
+	}
+	if got := gatherSystemd(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("second cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSystemdCollectorWithoutNRestarts(t *testing.T) {
+	resetSystemdMetrics()
+
+	// systemd старше 235: свойства NRestarts нет
+	client := &fakeSystemdClient{units: []systemdUnit{fakeUnit("app.service", "loaded", "active", "running")}}
+	collector := newSystemdCollector(newSystemdUnitFilter(deviceconfig.SystemdConfig{}))
+	if err := collector.collect(client); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, series := range gatherSystemd(t) {
+		if strings.HasPrefix(series, "systemd_service_restarts_total") {
+			t.Errorf("unexpected series %s", series)
+		}
+	}
+}
+
+func TestSystemdCollectorUnavailable(t *testing.T) {
+	resetSystemdMetrics()
+
+	collector := newSystemdCollector(newSystemdUnitFilter(deviceconfig.SystemdConfig{}))
+	client := &fakeSystemdClient{
+		units: []systemdUnit{
+			fakeUnit("app.service", "loaded", "active", "running"),
+			fakeUnit("backup.service", "loaded", "failed", "failed"),
+		},
+		restarts: map[string]uint32{"/org/freedesktop/systemd1/unit/app.service": 3},
+	}
+	if err := collector.collect(client); err != nil {
+		t.Fatal(err)
+	}
+	published := []string{
+		"systemd_failed_units{} 1",
+		"systemd_service_restarts_total{unit=app.service} 3",
+		"systemd_unit_active{type=service,unit=app.service} 1",
+		"systemd_unit_active{type=service,unit=backup.service} 0",
+		"systemd_unit_state{active_state=active,sub_state=running,type=service,unit=app.service} 1",
+		"systemd_unit_state{active_state=failed,sub_state=failed,type=service,unit=backup.service} 1",
+		"systemd_up{} 1",
+	}
+	if got := gatherSystemd(t); !reflect.DeepEqual(got, published) {
+		t.Fatalf("first cycle:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(published, "\n"))
+	}
+
+	client.err = errors.New("connection reset")
+	if err := collector.collect(client); err == nil {
+		t.Fatal("collect() succeeded with failing client")
+	}
+	// ошибка ListUnits не трогает опубликованные серии, их убирает unavailable
+	if got := gatherSystemd(t); !reflect.DeepEqual(got, published) {
+		t.Errorf("series after failed collect:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(published, "\n"))
+	}
+
+	collector.unavailable()
+	want := []string{
+		"systemd_failed_units{} 0",
+		"systemd_up{} 0",
+	}
+	if got := gatherSystemd(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("after unavailable:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// после восстановления соединения серии публикуются снова
+	client.err = nil
+	if err := collector.collect(client); err != nil {
+		t.Fatal(err)
+	}
+	if got := gatherSystemd(t); !reflect.DeepEqual(got, published) {
+		t.Errorf("series after reconnect:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(published, "\n"))
+	}
+}
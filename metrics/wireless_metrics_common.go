@@ -0,0 +1,53 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	WirelessInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "wireless_info",
+			Help: "Wireless association information: SSID and BSSID of the access point (value is always 1)",
+		},
+		[]string{"device", "ssid", "bssid"},
+	)
+
+	WirelessConnected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "wireless_connected",
+			Help: "Whether the wireless interface is associated with an access point (1 = connected)",
+		},
+		[]string{"device"},
+	)
+
+	WirelessSignal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "wireless_signal_dbm",
+			Help: "Wireless signal level in dBm",
+		},
+		[]string{"device"},
+	)
+
+	WirelessLinkQuality = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "wireless_link_quality",
+			Help: "Wireless link quality as reported by the driver in /proc/net/wireless",
+		},
+		[]string{"device"},
+	)
+
+	WirelessBitrate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "wireless_bitrate_mbps",
+			Help: "Wireless link bitrate in Mbit/s (direction = rx/tx)",
+		},
+		[]string{"device", "direction"},
+	)
+
+	WirelessFrequency = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "wireless_frequency_mhz",
+			Help: "Frequency of the wireless channel in MHz",
+		},
+		[]string{"device"},
+	)
+)
@@ -0,0 +1,244 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// wirelessProcStats — строка /proc/net/wireless.
+type wirelessProcStats struct {
+	Quality    float64
+	Level      float64
+	HasQuality bool
+	HasLevel   bool
+}
+
+// wirelessStation — вывод `iw dev <iface> link` (информация о станции из nl80211).
+type wirelessStation struct {
+	Connected     bool
+	SSID          string
+	BSSID         string
+	FrequencyMHz  float64
+	SignalDBm     float64
+	RxBitrateMbps float64
+	TxBitrateMbps float64
+	HasFrequency  bool
+	HasSignal     bool
+	HasRxBitrate  bool
+	HasTxBitrate  bool
+}
+
+// wirelessSeries — серии беспроводных метрик. Вызовы iw для каждого интерфейса
+// медленные, поэтому векторы не сбрасываются перед сбором: значения
+// обновляются на месте, а серии исчезнувших интерфейсов и прежних SSID/BSSID
+// удаляются после цикла.
+type wirelessSeries struct {
+	info      *gaugeSeries
+	connected *gaugeSeries
+	signal    *gaugeSeries
+	quality   *gaugeSeries
+	bitrate   *gaugeSeries
+	frequency *gaugeSeries
+}
+
+func RecordWirelessMetrics() {
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		series := &wirelessSeries{
+			info:      newGaugeSeries(WirelessInfo),
+			connected: newGaugeSeries(WirelessConnected),
+			signal:    newGaugeSeries(WirelessSignal),
+			quality:   newGaugeSeries(WirelessLinkQuality),
+			bitrate:   newGaugeSeries(WirelessBitrate),
+			frequency: newGaugeSeries(WirelessFrequency),
+		}
+
+		for {
+			recordWireless(series)
+			<-ticker.C
+		}
+	}()
+}
+
+func recordWireless(series *wirelessSeries) {
+	procStats := map[string]wirelessProcStats{}
+	if data, err := os.ReadFile("/proc/net/wireless"); err == nil {
+		procStats = parseProcNetWireless(string(data))
+	}
+
+	for _, iface := range wirelessInterfaces(netSysfsRoot) {
+		labels := prometheus.Labels{"device": iface}
+
+		station := queryWirelessStation(iface)
+
+		connected := 0.0
+		if station.Connected {
+			connected = 1
+			series.info.Set(prometheus.Labels{
+				"device": iface,
+				"ssid":   station.SSID,
+				"bssid":  station.BSSID,
+			}, 1)
+		}
+		series.connected.Set(labels, connected)
+
+		stats, hasStats := procStats[iface]
+		switch {
+		case station.HasSignal:
+			series.signal.Set(labels, station.SignalDBm)
+		case hasStats && stats.HasLevel:
+			series.signal.Set(labels, stats.Level)
+		}
+		if hasStats && stats.HasQuality {
+			series.quality.Set(labels, stats.Quality)
+		}
+
+		if station.HasRxBitrate {
+			series.bitrate.Set(prometheus.Labels{"device": iface, "direction": "rx"}, station.RxBitrateMbps)
+		}
+		if station.HasTxBitrate {
+			series.bitrate.Set(prometheus.Labels{"device": iface, "direction": "tx"}, station.TxBitrateMbps)
+		}
+		if station.HasFrequency {
+			series.frequency.Set(labels, station.FrequencyMHz)
+		}
+	}
+
+	for _, s := range []*gaugeSeries{series.info, series.connected, series.signal, series.quality, series.bitrate, series.frequency} {
+		s.Flush()
+	}
+}
+
+// wirelessInterfaces возвращает интерфейсы с каталогом wireless или phy80211 в sysfs.
+func wirelessInterfaces(root string) []string {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var result []string
+	for _, entry := range entries {
+		dir := filepath.Join(root, entry.Name())
+		for _, marker := range []string{"wireless", "phy80211"} {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				result = append(result, entry.Name())
+				break
+			}
+		}
+	}
+	return result
+}
+
+// parseProcNetWireless разбирает /proc/net/wireless:
+//
+//	Inter-| sta-|   Quality        |   Discarded packets  ...
+//	 face | tus | link level noise |  nwid  crypt ...
+//	 wlan0: 0000   70.  -40.  -256        0      0 ...
+func parseProcNetWireless(data string) map[string]wirelessProcStats {
+	result := make(map[string]wirelessProcStats)
+
+	for _, line := range strings.Split(data, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		iface := strings.TrimSpace(parts[0])
+		fields := strings.Fields(parts[1])
+		if iface == "" || len(fields) < 3 {
+			continue
+		}
+
+		stats := wirelessProcStats{}
+		if value, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "."), 64); err == nil {
+			stats.Quality, stats.HasQuality = value, true
+		}
+		// значения уровня >= 0 означают, что драйвер не сообщает его в dBm
+		if value, err := strconv.ParseFloat(strings.TrimSuffix(fields[2], "."), 64); err == nil && value < 0 {
+			stats.Level, stats.HasLevel = value, true
+		}
+		result[iface] = stats
+	}
+
+	return result
+}
+
+func queryWirelessStation(iface string) wirelessStation {
+	output, err := exec.Command("iw", "dev", iface, "link").Output()
+	if err != nil {
+		return wirelessStation{}
+	}
+	return parseIwLink(string(output))
+}
+
+// parseIwLink разбирает вывод `iw dev <iface> link`:
+//
+//	Connected to aa:bb:cc:dd:ee:ff (on wlan0)
+//		SSID: office
+//		freq: 5180
+//		signal: -52 dBm
+//		rx bitrate: 866.7 MBit/s VHT-MCS 9 80MHz short GI VHT-NSS 2
+//		tx bitrate: 650.0 MBit/s VHT-MCS 7 80MHz short GI VHT-NSS 2
+func parseIwLink(data string) wirelessStation {
+	station := wirelessStation{}
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "Connected to ") {
+			fields := strings.Fields(line)
+			if len(fields) >= 3 {
+				station.Connected = true
+				station.BSSID = strings.ToLower(fields[2])
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		fields := strings.Fields(value)
+
+		switch key {
+		case "SSID":
+			station.SSID = value
+		case "freq":
+			if len(fields) > 0 {
+				if parsed, err := strconv.ParseFloat(fields[0], 64); err == nil {
+					station.FrequencyMHz, station.HasFrequency = parsed, true
+				}
+			}
+		case "signal":
+			if len(fields) > 0 {
+				if parsed, err := strconv.ParseFloat(fields[0], 64); err == nil {
+					station.SignalDBm, station.HasSignal = parsed, true
+				}
+			}
+		case "rx bitrate":
+			if len(fields) > 0 {
+				if parsed, err := strconv.ParseFloat(fields[0], 64); err == nil {
+					station.RxBitrateMbps, station.HasRxBitrate = parsed, true
+				}
+			}
+		case "tx bitrate":
+			if len(fields) > 0 {
+				if parsed, err := strconv.ParseFloat(fields[0], 64); err == nil {
+					station.TxBitrateMbps, station.HasTxBitrate = parsed, true
+				}
+			}
+		}
+	}
+
+	return station
+}
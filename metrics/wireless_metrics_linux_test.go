@@ -0,0 +1,80 @@
+//go:build linux
+
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readWirelessFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "wireless", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseProcNetWireless(t *testing.T) {
+	got := parseProcNetWireless(readWirelessFixture(t, "proc_net_wireless"))
+	want := map[string]wirelessProcStats{
+		"wlp2s0": {Quality: 62, Level: -48, HasQuality: true, HasLevel: true},
+		// драйвер не сообщает уровень в dBm
+		"wlan1": {Quality: 0, HasQuality: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProcNetWireless() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseIwLink(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    wirelessStation
+	}{
+		{
+			fixture: "iw_link_connected",
+			want: wirelessStation{
+				Connected:     true,
+				SSID:          "office: 5G",
+				BSSID:         "3c:37:86:5a:9b:10",
+				FrequencyMHz:  5180,
+				SignalDBm:     -48,
+				RxBitrateMbps: 866.7,
+				TxBitrateMbps: 780,
+				HasFrequency:  true,
+				HasSignal:     true,
+				HasRxBitrate:  true,
+				HasTxBitrate:  true,
+			},
+		},
+		{
+			fixture: "iw_link_2ghz",
+			want: wirelessStation{
+				Connected:     true,
+				SSID:          "lab",
+				BSSID:         "00:11:22:33:44:55",
+				FrequencyMHz:  2437,
+				SignalDBm:     -71,
+				RxBitrateMbps: 1,
+				HasFrequency:  true,
+				HasSignal:     true,
+				HasRxBitrate:  true,
+			},
+		},
+		{
+			fixture: "iw_link_disconnected",
+			want:    wirelessStation{},
+		},
+	}
+
+	for _, tt := range tests {
+		got := parseIwLink(readWirelessFixture(t, tt.fixture))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseIwLink() = %+v, want %+v", tt.fixture, got, tt.want)
+		}
+	}
+}
@@ -12,6 +12,11 @@ const (
 	uuidFileName = "hardware_uuid"
 )
 
+// StateDir возвращает каталог для хранения состояния агента (NCM_STATE_DIR).
+func StateDir() string {
+	return stateDir()
+}
+
 func stateDir() string {
 	if dir := os.Getenv("NCM_STATE_DIR"); dir != "" {
 		return dir